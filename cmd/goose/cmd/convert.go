@@ -5,9 +5,11 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/advancedlogic/GoOse/pkg/goose"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 // convertCmd represents the convert command
@@ -39,7 +41,7 @@ func init() {
 
 	// Add flags for the convert command
 	convertCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
-	convertCmd.Flags().StringP("format", "f", "text", "Output format: text, json")
+	convertCmd.Flags().StringP("format", "f", "text", "Output format: text, json, markdown")
 	convertCmd.Flags().BoolP("meta", "m", false, "Include metadata (title, author, etc.)")
 }
 
@@ -58,6 +60,10 @@ func extractContent(url, outputFile, format string, showMeta bool) error {
 	switch format {
 	case "json":
 		output = formatJSON(article, showMeta)
+	case "markdown", "md":
+		if output, err = formatMarkdown(article, showMeta); err != nil {
+			return err
+		}
 	case "text":
 		fallthrough
 	default:
@@ -164,6 +170,42 @@ func formatJSON(article *goose.Article, showMeta bool) string {
 	return result
 }
 
+// frontMatter is the metadata written before the Markdown of an article
+type frontMatter struct {
+	Title       string     `yaml:"title,omitempty"`
+	Description string     `yaml:"description,omitempty"`
+	Keywords    string     `yaml:"keywords,omitempty"`
+	Published   *time.Time `yaml:"published,omitempty"`
+	Domain      string     `yaml:"domain,omitempty"`
+	URL         string     `yaml:"url,omitempty"`
+}
+
+func formatMarkdown(article *goose.Article, showMeta bool) (string, error) {
+	var result string
+
+	if showMeta {
+		// YAML front matter, as read by most static site generators, without the invalid
+		// UTF-8 that the encoder would write as !!binary
+		text := func(s string) string { return strings.ToValidUTF8(s, "\uFFFD") }
+		meta, err := yaml.Marshal(frontMatter{
+			Title:       text(article.Title),
+			Description: text(article.MetaDescription),
+			Keywords:    text(article.MetaKeywords),
+			Published:   article.PublishDate,
+			Domain:      text(article.Domain),
+			URL:         text(article.CanonicalLink),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to write the front matter: %w", err)
+		}
+		result += "---\n" + string(meta) + "---\n\n"
+	}
+
+	result += article.Markdown()
+
+	return result, nil
+}
+
 func escapeJSON(s string) string {
 	// Simple JSON escaping
 	s = fmt.Sprintf("%q", s)
@@ -6,16 +6,17 @@ toolchain go1.24.5
 
 require (
 	github.com/PuerkitoBio/goquery v1.4.1
+	github.com/andybalholm/brotli v1.1.0
 	github.com/araddon/dateparse v0.0.0-20180729174819-cfd92a431d0e
 	github.com/fatih/set v0.2.1
 	github.com/gigawattio/window v0.0.0-20180317192513-0f5467e35573
-	github.com/go-resty/resty/v2 v2.0.0
 	github.com/jaytaylor/html2text v0.0.0-20180606194806-57d518f124b0
 	github.com/pkg/errors v0.9.1
 	github.com/spf13/cobra v1.9.1
 	github.com/spf13/viper v1.20.1
 	golang.org/x/net v0.33.0
 	golang.org/x/text v0.21.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -40,5 +41,4 @@ require (
 	golang.org/x/sys v0.29.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/mgo.v2 v2.0.0-20190816093944-a6b53ec6cb22 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
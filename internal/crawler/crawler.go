@@ -1,24 +1,37 @@
 package crawler
 
 import (
+	"context"
 	"errors"
+	"mime"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/advancedlogic/GoOse/internal/extractor"
+	"github.com/advancedlogic/GoOse/internal/fetcher"
+	"github.com/advancedlogic/GoOse/internal/types"
 	"github.com/advancedlogic/GoOse/internal/utils"
-	"github.com/advancedlogic/GoOse/pkg/goose"
 )
 
 // Crawler can fetch the target HTML page
 type Crawler struct {
-	config  goose.Configuration
+	config  types.Configuration
 	Charset string
+	// FetchTime is when the page was fetched, against which the relative dates are resolved
+	// (the current time when zero)
+	FetchTime time.Time
+	// ManifestFetcher, when set, fetches the web app manifest of the page to read its icons
+	ManifestFetcher fetcher.Fetcher
+
+	// charset used to decode the last preprocessed page, and where it was found
+	detectedCharset string
+	charsetSource   string
 }
 
 // NewCrawler returns a crawler object initialised with the URL and the [optional] raw HTML body
-func NewCrawler(config goose.Configuration) Crawler {
+func NewCrawler(config types.Configuration) Crawler {
 	return Crawler{
 		config:  config,
 		Charset: "",
@@ -26,6 +39,12 @@ func NewCrawler(config goose.Configuration) Crawler {
 }
 
 func getCharsetFromContentType(cs string) string {
+	if strings.Contains(cs, "/") {
+		// a full media type, e.g. "text/html; charset=utf-8"
+		if _, params, err := mime.ParseMediaType(cs); err == nil {
+			return utils.NormaliseCharset(params["charset"])
+		}
+	}
 	cs = strings.ToLower(strings.Replace(cs, " ", "", -1))
 	cs = strings.TrimPrefix(cs, "text/html;charset=")
 	cs = strings.TrimPrefix(cs, "text/xhtml;charset=")
@@ -39,6 +58,14 @@ func (c *Crawler) SetCharset(cs string) {
 	c.Charset = getCharsetFromContentType(cs)
 }
 
+// SetFetchTime sets when the page was fetched, e.g. from the Date HTTP header. It is
+// ignored when it cannot be parsed
+func (c *Crawler) SetFetchTime(date string) {
+	if t, err := http.ParseTime(date); err == nil {
+		c.FetchTime = t
+	}
+}
+
 // GetContentType returns the Content-Type string extracted from the meta tags
 func (c Crawler) GetContentType(document *goquery.Document) string {
 	var attr string
@@ -63,21 +90,20 @@ func (c Crawler) GetCharset(document *goquery.Document) string {
 	}
 
 	// <meta charset="utf-8">
-	selection := document.Find("meta").EachWithBreak(func(i int, s *goquery.Selection) bool {
-		_, exists := s.Attr("charset")
-		return !exists
-	})
-
-	if selection != nil {
-		cs, _ := selection.Attr("charset")
-		return utils.NormaliseCharset(cs)
+	if cs, exists := document.Find("meta[charset]").First().Attr("charset"); exists {
+		return utils.NormaliseCharset(strings.TrimSpace(cs))
 	}
 
 	return ""
 }
 
 // Preprocess fetches the HTML page if needed, converts it to UTF-8 and applies
-// some text normalisation to guarantee better results when extracting the content
+// some text normalisation to guarantee better results when extracting the content.
+// The charset is taken, by order of precedence, from the byte order mark, the
+// charset forced with SetCharset (i.e. the HTTP headers), the meta tags and
+// finally a guess based on the content itself, which also replaces meta tags
+// that do not match the content or name an unsupported charset.
+// A DecodeError is returned when the byte order mark or the forced charset is not supported
 func (c *Crawler) Preprocess(RawHTML string) (*goquery.Document, error) {
 	var err error
 
@@ -85,6 +111,20 @@ func (c *Crawler) Preprocess(RawHTML string) (*goquery.Document, error) {
 		return nil, errors.New("cannot process empty HTML content")
 	}
 
+	cs, source := "", ""
+	if bom, size := utils.BOMCharset(RawHTML); bom != "" {
+		cs, source = bom, types.CharsetSourceBOM
+		RawHTML = RawHTML[size:]
+	} else if c.Charset != "" {
+		cs, source = c.Charset, types.CharsetSourceHTTP
+	}
+	if "" != cs && "UTF-8" != cs {
+		// the net/html parser and goquery require UTF-8 data
+		if RawHTML, err = utils.ConvertToUTF8(RawHTML, cs); err != nil {
+			return nil, &types.DecodeError{Charset: cs, Err: err}
+		}
+	}
+
 	RawHTML = c.addSpacesBetweenTags(RawHTML)
 
 	reader := strings.NewReader(RawHTML)
@@ -93,27 +133,43 @@ func (c *Crawler) Preprocess(RawHTML string) (*goquery.Document, error) {
 		return nil, err
 	}
 
-	cs := c.GetCharset(document)
-	//log.Println("-------------------------------------------CHARSET:", cs)
-	if "" != cs && "UTF-8" != cs {
-		// the net/html parser and goquery require UTF-8 data
-		RawHTML = utils.UTF8encode(RawHTML, cs)
-		reader = strings.NewReader(RawHTML)
-		if document, err = goquery.NewDocumentFromReader(reader); err != nil {
-			return nil, err
+	if cs == "" {
+		cs, source = c.GetCharset(document), types.CharsetSourceMeta
+		if cs == "" || !utils.IsSupportedCharset(cs) || !utils.IsPlausibleCharset(RawHTML, cs) {
+			cs, source = utils.GuessCharset(RawHTML), types.CharsetSourceGuess
+		}
+		//log.Println("-------------------------------------------CHARSET:", cs)
+		if "" != cs && "UTF-8" != cs {
+			if RawHTML, err = utils.ConvertToUTF8(RawHTML, cs); err != nil {
+				return nil, &types.DecodeError{Charset: cs, Err: err}
+			}
+			reader = strings.NewReader(RawHTML)
+			if document, err = goquery.NewDocumentFromReader(reader); err != nil {
+				return nil, err
+			}
 		}
 	}
+	c.detectedCharset, c.charsetSource = cs, source
 
 	return document, nil
 }
 
 // Crawl fetches the HTML body and returns an Article
-func (c Crawler) Crawl(RawHTML string, url string) (*goose.Article, error) {
-	article := new(goose.Article)
+func (c Crawler) Crawl(RawHTML string, url string) (*types.Article, error) {
+	return c.CrawlContext(context.Background(), RawHTML, url)
+}
+
+// CrawlContext is like Crawl but checks the context between (and within) the extraction
+// stages, returning the context error as soon as it is done
+func (c Crawler) CrawlContext(ctx context.Context, RawHTML string, url string) (*types.Article, error) {
+	article := new(types.Article)
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	document, err := c.Preprocess(RawHTML)
 	if nil != err {
-		return nil, err
+		return nil, &types.ExtractionError{URL: url, Phase: types.PhasePreprocess, Err: err}
 	}
 	if nil == document {
 		return article, nil
@@ -127,11 +183,23 @@ func (c Crawler) Crawl(RawHTML string, url string) (*goose.Article, error) {
 	}
 	article.FinalURL = url
 	article.Doc = document
+	article.Charset = c.detectedCharset
+	article.CharsetSource = c.charsetSource
 
-	article.Title = extr.GetTitle(document)
-	article.TitleUnmodified = article.Title
+	article.TitleUnmodified = extr.GetTitleUnmodified(document)
+	article.Title = extr.GetTitleFromUnmodifiedTitle(article.TitleUnmodified)
 	article.MetaLang = extr.GetMetaLanguage(document)
 	article.MetaFavicon = extr.GetFavicon(document)
+	article.Icons = extr.GetIcons(document, article.FinalURL)
+	if len(article.Icons) > 0 {
+		// the favicon is the first icon of the page, made absolute
+		article.MetaFavicon = article.Icons[0].URL
+	}
+	if c.ManifestFetcher != nil {
+		if manifestURL := extr.GetManifestURL(document, article.FinalURL); manifestURL != "" {
+			article.Icons = append(article.Icons, c.manifestIcons(ctx, manifestURL)...)
+		}
+	}
 
 	article.MetaDescription = extr.GetMetaContentWithSelector(document, "meta[name#=(?i)^description$]")
 	article.MetaKeywords = extr.GetMetaContentWithSelector(document, "meta[name#=(?i)^keywords$]")
@@ -139,38 +207,117 @@ func (c Crawler) Crawl(RawHTML string, url string) (*goose.Article, error) {
 	if "" == article.CanonicalLink {
 		article.CanonicalLink = article.FinalURL
 	}
+	// the variants of the page (relative, tracked, AMP or mobile links) share the same canonical link and hash
+	if normalized, err := utils.NormalizeURL(article.CanonicalLink, article.FinalURL, c.config.TrackingParams); err == nil {
+		article.CanonicalLink = normalized
+		article.LinkHash = utils.HashURL(normalized)
+	}
 	article.Domain = extr.GetDomain(article.CanonicalLink)
 	article.Tags = extr.GetTags(document)
-
-	if c.config.ExtractPublishDate {
-		if timestamp := extr.GetPublishDate(document); timestamp != nil {
-			article.PublishDate = timestamp
+	article.OpenGraph = extr.GetOpenGraph(document, article.FinalURL)
+	article.TwitterCard = extr.GetTwitterCard(document, article.FinalURL)
+
+	// the JSON-LD scripts and the markup are read before the cleaner removes them, and
+	// take precedence over the heuristics. The headline of the JSON-LD replaces the title
+	// only when it completes it, the one of the markup only stands in for a missing title
+	jsonld := extr.GetJSONLD(document, article.FinalURL)
+	article.Metadata = types.MergeMetadata(
+		jsonld,
+		extr.GetMicrodata(document, article.FinalURL),
+		extr.GetRDFa(document, article.FinalURL),
+		extr.GetMicroformats(document, article.FinalURL),
+	)
+	if jsonld != nil && isBetterHeadline(jsonld.Headline, article.Title, article.TitleUnmodified) {
+		article.Title = jsonld.Headline
+	} else if article.Title == "" && article.Metadata != nil {
+		article.Title = article.Metadata.Headline
+	}
+	// the citation_* tags of the scholarly articles describe them best
+	article.Citation = extr.GetCitation(document, article.FinalURL)
+	if article.Citation != nil && article.Citation.Title != "" {
+		article.Title = article.Citation.Title
+	}
+	article.Authors = extr.GetAuthors(document, article.FinalURL, article.Metadata)
+	// the breadcrumbs are read before the cleaner removes the <nav> elements
+	article.Breadcrumbs = extr.GetBreadcrumbs(document, article.FinalURL)
+	article.Section = extr.GetSection(article)
+
+	// the text is scanned for a date only when no structured source has one
+	extr.ResolveDates(document, article)
+	if article.PublishDate == nil && c.config.ExtractPublishDate {
+		fetchTime := c.FetchTime
+		if fetchTime.IsZero() {
+			fetchTime = time.Now()
+		}
+		timestamp, err := extr.GetPublishDateAtContext(ctx, document, fetchTime, extractor.PageLocation(article))
+		if err != nil {
+			return nil, &types.ExtractionError{URL: url, Phase: types.PhasePublishDate, Err: err}
+		}
+		if timestamp != nil {
+			article.PublishDate, article.PublishDateSource = timestamp, extractor.TextDateSource()
 		}
 	}
 
+	consentProvider := extractor.RemoveConsentWall(document, url)
+
+	// the cleaner unwraps the <em> elements, whose text is emphasised in the blocks
+	emphasis := extractor.EmphasisNodes(article.Doc)
 	cleaner := extractor.NewCleaner(c.config)
-	article.Doc = cleaner.Clean(article.Doc)
+	if article.Doc, err = cleaner.CleanContext(ctx, article.Doc); err != nil {
+		return nil, &types.ExtractionError{URL: url, Phase: types.PhaseClean, Err: err}
+	}
 
+	// og:image is declared for the article as well, and is usually the large version of the
+	// image whereas the structured metadata often gives a thumbnail. Both come before the
+	// heuristics
 	article.TopImage = extractor.OpenGraphResolver(document)
+	if article.TopImage == "" && article.Metadata != nil {
+		article.TopImage = article.Metadata.Image
+	}
 	if article.TopImage == "" {
 		article.TopImage = extractor.WebPageResolver(article)
 	}
 
-	article.TopNode = extr.CalculateBestNode(document)
+	article.TopNode, err = extr.CalculateBestNodeContext(ctx, document)
+	if err != nil && (c.config.RequireContent || !errors.Is(err, types.ErrNoContent)) {
+		return nil, &types.ExtractionError{URL: url, Phase: types.PhaseBestNode, Err: err}
+	}
 	if article.TopNode != nil {
 		article.TopNode = extr.PostCleanup(article.TopNode)
 
-		article.CleanedText, article.Links = extr.GetCleanTextAndLinks(article.TopNode, article.MetaLang)
+		article.Blocks, article.Links, err = extr.GetBlocksAndLinksContext(ctx, article.TopNode, article.MetaLang, article.FinalURL, emphasis)
+		if err != nil {
+			return nil, &types.ExtractionError{URL: url, Phase: types.PhaseFormat, Err: err}
+		}
+		article.Blocks = extractor.StripBylineBlocks(article.Blocks, article.Authors)
+		article.CleanedText = types.BlocksCleanedText(article.Blocks)
 
 		videoExtractor := extractor.NewVideoExtractor()
 		article.Movies = videoExtractor.GetVideos(document)
 	}
 
+	if extractor.IsConsentWall(consentProvider, article.CleanedText) {
+		return nil, &types.ConsentRequiredError{URL: url, Provider: consentProvider}
+	}
+
 	article.Delta = time.Now().UnixNano() - startTime
 
 	return article, nil
 }
 
+// manifestIcons returns the icons of the web app manifest, none when it cannot be fetched or parsed
+func (c Crawler) manifestIcons(ctx context.Context, manifestURL string) []types.Icon {
+	result, err := c.ManifestFetcher.Fetch(ctx, manifestURL)
+	if err != nil || result.StatusCode != http.StatusOK {
+		return nil
+	}
+	icons, err := extractor.ParseManifestIcons(result.Body, result.FinalURL)
+	if err != nil {
+		return nil
+	}
+	return icons
+}
+
 // In many cases, like at the end of each <li> element or between </span><span> tags,
 // we need to add spaces, otherwise the text on either side will get joined together into one word.
 // This method also adds newlines after each </p> tag to preserve paragraphs.
@@ -181,3 +328,23 @@ func (c Crawler) addSpacesBetweenTags(text string) string {
 	text = strings.Replace(text, "</li>", "</li>\n", -1)
 	return strings.Replace(text, "</p>", "</p>\n", -1)
 }
+
+// isBetterHeadline tells whether a headline should replace the title found by the heuristics:
+// when there is none, when it was truncated from the headline, or when the headline is the part
+// of the <title> the heuristics cut wrongly. The headlines written for the social networks or
+// the search engines, unlike the <title>, are left out
+func isBetterHeadline(headline string, title string, rawTitle string) bool {
+	headline = strings.TrimSpace(headline)
+	if headline == "" {
+		return false
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return true
+	}
+	truncated := strings.TrimSpace(strings.TrimRight(title, ".…"))
+	if strings.HasPrefix(strings.ToLower(headline), strings.ToLower(truncated)) {
+		return true
+	}
+	return strings.Contains(strings.ToLower(rawTitle), strings.ToLower(headline))
+}
@@ -5,20 +5,20 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/pkg/errors"
 	"github.com/advancedlogic/GoOse/internal/extractor"
+	"github.com/advancedlogic/GoOse/internal/types"
 	"github.com/advancedlogic/GoOse/internal/utils"
-	"github.com/advancedlogic/GoOse/pkg/goose"
+	"github.com/pkg/errors"
 )
 
 // Crawler can fetch the target HTML page
 type CrawlerShort struct {
-	config  goose.Configuration
+	config  types.Configuration
 	Charset string
 }
 
 // NewCrawler returns a crawler object initialised with the URL and the [optional] raw HTML body
-func NewCrawlerShort(config goose.Configuration) CrawlerShort {
+func NewCrawlerShort(config types.Configuration) CrawlerShort {
 	return CrawlerShort{
 		config:  config,
 		Charset: "",
@@ -96,8 +96,8 @@ func (c *CrawlerShort) Preprocess(RawHTML string) (*goquery.Document, error) {
 }
 
 // Crawl fetches the HTML body and returns an Article
-func (c CrawlerShort) Crawl(RawHTML, url string) (*goose.Article, error) {
-	article := new(goose.Article)
+func (c CrawlerShort) Crawl(RawHTML, url string) (*types.Article, error) {
+	article := new(types.Article)
 
 	document, err := c.Preprocess(RawHTML)
 	if err != nil {
@@ -0,0 +1,316 @@
+package extractor
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/advancedlogic/GoOse/internal/types"
+)
+
+const (
+	// maxBylineLength is the length, in characters, of the longest text taken for a byline
+	maxBylineLength = 120
+	// maxBylines is the number of byline patterns looked for from the top of the page
+	maxBylines = 3
+	// maxAuthorWords is the number of words of the longest name
+	maxAuthorWords = 5
+)
+
+var (
+	// bylinePrefix matches the "By" starting a byline, in the main languages of the stopwords
+	bylinePrefix = regexp.MustCompile(`(?i)^\s*(by|written by|words by|par|por|von|door|av|di|oleh|от|автор:?)\s+`)
+	// authorSeparators split the names of a byline
+	authorSeparators = regexp.MustCompile(`(?i)\s*[,;&]\s*|\s+(?:and|und|et|y|en|och|dan|и)\s+`)
+	// nameParticles are the lowercase words allowed in a name
+	nameParticles = map[string]bool{"de": true, "da": true, "di": true, "del": true, "der": true, "van": true, "von": true, "le": true, "la": true, "bin": true, "al": true}
+	// authorSuffix is what follows the names, e.g. "| Reuters" or "- Updated 5 March"
+	authorSuffix = regexp.MustCompile(`\s+(?:[|–—]|-\s).*$`)
+	// bylineMonth and bylineWord end the names of a byline, e.g. "By Jane Doe Nov. 5, 2015" or "By Jane Doe on Monday"
+	bylineMonth = regexp.MustCompile(`(?i)^(jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec|january|february|march|april|june|july|august|september|october|november|december)\.?,?$`)
+	bylineWord  = regexp.MustCompile(`^(on|at|in|updated|published|posted|Updated|Published|Posted)$`)
+	// twitterHandle is a trailing "@handle"
+	twitterHandle = regexp.MustCompile(`\s*@\w+$`)
+	// notAuthors are the roles and words that bylines mix with the names
+	notAuthors = regexp.MustCompile(`(?i)^(staff|staff writer|reporter|correspondent|editor|contributor|columnist|updated|published|posted|the associated press|associated press|reuters|afp|news desk)$`)
+)
+
+// GetAuthors returns the authors of the article, from the most to the least reliable source:
+// the JSON-LD metadata, itemprop=author, rel=author links, the meta tags and the bylines near
+// the headline. It must be called before the cleaner removes the bylines
+func (extr *ContentExtractor) GetAuthors(document *goquery.Document, pageURL string, metadata *types.Metadata) []types.Entity {
+	base, _ := url.Parse(pageURL)
+	var authors authorList
+	if metadata != nil {
+		for _, author := range metadata.Authors {
+			authors.add(author.Name, author.URL)
+		}
+	}
+
+	document.Find("[itemprop~=author]").Each(func(i int, s *goquery.Selection) {
+		name := s.Find("[itemprop~=name]").First()
+		if name.Length() == 0 {
+			name = s
+		}
+		text, _ := name.Attr("content")
+		if text == "" {
+			text = name.Text()
+		}
+		href, _ := s.Find("[itemprop~=url]").Attr("href")
+		if href == "" {
+			href, _ = s.Attr("href")
+		}
+		authors.addAll(splitByline(text), resolveURL(base, href))
+	})
+
+	document.Find("a[rel~=author], link[rel~=author]").Each(func(i int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		text := s.Text()
+		if title, ok := s.Attr("title"); ok && strings.TrimSpace(text) == "" {
+			text = title
+		}
+		authors.addAll(splitByline(text), resolveURL(base, href))
+	})
+
+	for _, name := range []string{"author", "article:author", "dc.creator", "byl", "sailthru.author", "parsely-author"} {
+		document.Find("meta").Each(func(i int, s *goquery.Selection) {
+			attr, _ := s.Attr("name")
+			if attr == "" {
+				attr, _ = s.Attr("property")
+			}
+			if !strings.EqualFold(attr, name) {
+				return
+			}
+			content, _ := s.Attr("content")
+			if u := resolveURL(nil, content); u != "" {
+				// article:author is often the profile URL
+				authors.add("", u)
+				return
+			}
+			authors.addAll(splitByline(content), "")
+		})
+	}
+
+	bylines := 0
+	document.Find("p, span, div, address, small, strong, em, li").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		text := normaliseSpaces(s.Text())
+		if len([]rune(text)) > maxBylineLength || !bylinePrefix.MatchString(text) {
+			return true
+		}
+		names := bylineNames(firstLine(s.Text()))
+		if len(names) == 0 {
+			return true
+		}
+		for _, name := range names {
+			href := ""
+			s.Find("a[href]").EachWithBreak(func(j int, a *goquery.Selection) bool {
+				if strings.EqualFold(normaliseAuthor(a.Text()), name) {
+					href, _ = a.Attr("href")
+					return false
+				}
+				return true
+			})
+			authors.add(name, resolveURL(base, href))
+		}
+		bylines++
+		return bylines < maxBylines
+	})
+	return authors.entities()
+}
+
+// firstLine returns the first line of a text, along the next one when the first is just "By"
+func firstLine(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = normaliseSpaces(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	if len(lines) > 1 && bylinePrefix.MatchString(lines[0]+" ") && bylinePrefix.ReplaceAllString(lines[0]+" ", "") == "" {
+		return lines[0] + " " + lines[1]
+	}
+	return lines[0]
+}
+
+// StripBylineBlocks removes the byline lines of the paragraphs of the blocks, i.e. the short lines
+// starting with "By" and naming some of the authors, and the paragraphs left empty
+func StripBylineBlocks(blocks []types.Block, authors []types.Entity) []types.Block {
+	if len(authors) == 0 {
+		return blocks
+	}
+	known := make(map[string]bool)
+	for _, author := range authors {
+		known[strings.ToLower(author.Name)] = true
+	}
+	keep := func(line string) bool {
+		if len([]rune(line)) > maxBylineLength || !bylinePrefix.MatchString(line) {
+			return true
+		}
+		names := bylineNames(line)
+		for _, name := range names {
+			if !known[strings.ToLower(name)] {
+				return true
+			}
+		}
+		return len(names) == 0
+	}
+	var kept []types.Block
+	for _, block := range blocks {
+		if block.Type == types.BlockParagraph {
+			if block = filterLines(block, keep); block.Text == "" {
+				continue
+			}
+		}
+		kept = append(kept, block)
+	}
+	return kept
+}
+
+// splitByline returns the names of a byline such as "By Jane Doe and John Smith | Reuters"
+func splitByline(byline string) []string {
+	byline = normaliseSpaces(byline)
+	byline = bylinePrefix.ReplaceAllString(byline, "")
+	byline = authorSuffix.ReplaceAllString(byline, "")
+	words := strings.Fields(byline)
+	for i := range words {
+		if endsByline(words, i) {
+			words = words[:i]
+			break
+		}
+	}
+	byline = strings.Join(words, " ")
+	var names []string
+	for _, part := range authorSeparators.Split(byline, -1) {
+		if name := normaliseAuthor(part); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// endsByline tells whether the names of a byline end before its i-th word: a number,
+// a date (a month followed by a number) or a word such as "on" or "updated"
+func endsByline(words []string, i int) bool {
+	if strings.IndexFunc(words[i], unicode.IsDigit) != -1 || bylineWord.MatchString(words[i]) {
+		return true
+	}
+	return bylineMonth.MatchString(words[i]) && i+1 < len(words) && strings.IndexFunc(words[i+1], unicode.IsDigit) != -1
+}
+
+// bylineNames returns the names of a byline found in the text of the page, or nil when
+// one of them does not look like a full name, e.g. in "By the way"
+func bylineNames(text string) []string {
+	names := splitByline(text)
+	for _, name := range names {
+		words := strings.Fields(name)
+		if len(words) < 2 {
+			return nil
+		}
+		for _, word := range words {
+			if first := []rune(word)[0]; !unicode.IsUpper(first) && !nameParticles[word] {
+				return nil
+			}
+		}
+	}
+	return names
+}
+
+// normaliseAuthor cleans up a name, returning "" when it does not look like one
+func normaliseAuthor(name string) string {
+	name = normaliseSpaces(name)
+	name = bylinePrefix.ReplaceAllString(name, "")
+	name = twitterHandle.ReplaceAllString(name, "")
+	name = strings.TrimFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '.' && r != ')'
+	})
+	words := strings.Fields(name)
+	if len(words) == 0 || len(words) > maxAuthorWords || notAuthors.MatchString(name) {
+		return ""
+	}
+	for _, r := range name {
+		if unicode.IsDigit(r) || r == '/' || r == ':' {
+			return ""
+		}
+	}
+	if len(words) > 1 && strings.ToUpper(name) == name && strings.ToLower(name) != name {
+		// "JANE DOE" becomes "Jane Doe", but the initials and the acronyms are kept
+		for i, word := range words {
+			if !strings.Contains(word, ".") {
+				words[i] = titleCase(word)
+			}
+		}
+		name = strings.Join(words, " ")
+	}
+	return name
+}
+
+// titleCase capitalises the first letter of each part of a word, e.g. "freytas-tamura" or "o'neil"
+func titleCase(word string) string {
+	runes := []rune(strings.ToLower(word))
+	for i := range runes {
+		if i == 0 || runes[i-1] == '-' || runes[i-1] == '\'' {
+			runes[i] = unicode.ToUpper(runes[i])
+		}
+	}
+	return string(runes)
+}
+
+func normaliseSpaces(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// authorList de-duplicates the authors by name, case-insensitively, keeping the
+// first name found and the first profile URL
+type authorList struct {
+	authors []types.Entity
+	urls    map[string]bool
+}
+
+func (l *authorList) add(name string, profile string) {
+	name = normaliseAuthor(name)
+	if name == "" {
+		// a profile URL alone completes the author it belongs to, if any
+		if profile != "" && len(l.authors) == 1 && l.authors[0].URL == "" {
+			l.authors[0].URL = profile
+		}
+		return
+	}
+	for i := range l.authors {
+		if strings.EqualFold(l.authors[i].Name, name) {
+			if l.authors[i].URL == "" && !l.urls[profile] {
+				l.authors[i].URL = profile
+			}
+			return
+		}
+	}
+	if l.urls[profile] {
+		// the same profile under another name
+		profile = ""
+	}
+	if l.urls == nil {
+		l.urls = make(map[string]bool)
+	}
+	if profile != "" {
+		l.urls[profile] = true
+	}
+	l.authors = append(l.authors, types.Entity{Name: name, URL: profile})
+}
+
+// addAll adds the names, the profile URL going to the first one
+func (l *authorList) addAll(names []string, profile string) {
+	for i, name := range names {
+		if i > 0 {
+			profile = ""
+		}
+		l.add(name, profile)
+	}
+}
+
+func (l *authorList) entities() []types.Entity {
+	return l.authors
+}
@@ -0,0 +1,586 @@
+package extractor
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/advancedlogic/GoOse/internal/types"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// inlineAtoms are the elements whose content flows within the text of a block
+var inlineAtoms = map[atom.Atom]bool{
+	atom.Abbr: true, atom.B: true, atom.Bdi: true, atom.Bdo: true, atom.Cite: true, atom.Code: true,
+	atom.Data: true, atom.Del: true, atom.Dfn: true, atom.Em: true, atom.Font: true, atom.I: true,
+	atom.Ins: true, atom.Kbd: true, atom.Label: true, atom.Mark: true, atom.Q: true, atom.S: true,
+	atom.Samp: true, atom.Small: true, atom.Span: true, atom.Strike: true, atom.Strong: true,
+	atom.Sub: true, atom.Sup: true, atom.Time: true, atom.Tt: true, atom.U: true, atom.Var: true,
+}
+
+// styleTypes are the Style* constants of the elements styling their text
+var styleTypes = map[atom.Atom]string{
+	atom.Strong: types.StyleStrong, atom.B: types.StyleStrong, atom.Em: types.StyleEmphasis, atom.I: types.StyleEmphasis,
+	atom.Code: types.StyleCode, atom.Kbd: types.StyleCode, atom.Samp: types.StyleCode, atom.Tt: types.StyleCode,
+}
+
+// skippedAtoms are the elements without any content to read
+var skippedAtoms = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true, atom.Button: true,
+	atom.Input: true, atom.Select: true, atom.Textarea: true, atom.Svg: true, atom.Math: true,
+	atom.Link: true, atom.Meta: true, atom.Head: true,
+}
+
+// headingLevels are the levels of the heading elements
+var headingLevels = map[atom.Atom]int{atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6}
+
+// mediaAtoms are the elements of the images and the embeds
+var mediaAtoms = map[atom.Atom]bool{
+	atom.Img: true, atom.Figure: true, atom.Iframe: true, atom.Video: true, atom.Audio: true, atom.Embed: true, atom.Object: true,
+}
+
+// layoutTableContent are the elements found in the cells of the tables used for the layout
+var layoutTableContent = "table, p, div, ul, ol, blockquote, pre, h1, h2, h3, h4, h5, h6"
+
+// codeLanguage matches the language in the class of a code block, e.g. "language-go" or "lang-js"
+var codeLanguage = regexp.MustCompile(`(?:^|\s)(?:language|lang)-([\w+#-]+)`)
+
+// blockBuilder splits a node into blocks, gathering the inline content of the current one
+type blockBuilder struct {
+	base   *url.URL
+	blocks []types.Block
+	// emphasis are the text nodes of the <em> elements unwrapped by the cleaner
+	emphasis map[*html.Node]bool
+
+	text  strings.Builder
+	links []types.Link
+	// open are the links being read, their offset still -1 until their first character
+	open   []*types.Link
+	styles []types.Style
+	// openStyles are the styles being read, nil when nested in a style of the same type
+	openStyles []*types.Style
+	// space tells whether a space separates the next word from the text
+	space bool
+}
+
+// EmphasisNodes returns the text nodes within the <em> elements of the document, which the
+// cleaner unwraps, for GetBlocksAndLinksContext
+func EmphasisNodes(document *goquery.Document) map[*html.Node]bool {
+	emphasis := make(map[*html.Node]bool)
+	var f func(*html.Node)
+	f = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.TextNode {
+				emphasis[child] = true
+			}
+			f(child)
+		}
+	}
+	for _, node := range document.Find("em").Nodes {
+		f(node)
+	}
+	return emphasis
+}
+
+// getBlocks returns the blocks of the top node, after the removal of the navigation lines
+// and of the repeated lines
+func getBlocks(nodes []*html.Node, base *url.URL, emphasis map[*html.Node]bool) []types.Block {
+	b := &blockBuilder{base: base, emphasis: emphasis}
+	for _, node := range nodes {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			b.walk(child)
+		}
+		// the top node can be made of several paragraphs
+		b.flush(types.BlockParagraph, 0)
+	}
+	return filterBlockLines(b.blocks)
+}
+
+// isStructuredContent tells whether a child of the top node is kept by the post cleanup and the
+// formatter although it has no paragraph or few words: a heading, a list, a quote, a code block,
+// a data table, a figure, an image or a video, without too many links or a negative score
+func (extr *ContentExtractor) isStructuredContent(node *html.Node) bool {
+	if node.Type != html.ElementNode {
+		return false
+	}
+	s := goquery.NewDocumentFromNode(node).Selection
+	if score, err := strconv.Atoi(attr(node, "gravityScore")); err == nil && score < 1 {
+		return false
+	}
+	if extr.isHighLinkDensity(s) {
+		return false
+	}
+	switch node.DataAtom {
+	case atom.H2, atom.H3, atom.H4:
+		return strings.TrimSpace(s.Text()) != ""
+	case atom.H5, atom.H6, atom.Ul, atom.Ol, atom.Blockquote, atom.Pre:
+		// unless the formatter would have removed them for having less than 5 words
+		return len(strings.Fields(s.Text())) >= 5
+	case atom.Figure:
+		return strings.TrimSpace(s.Text()) != "" || s.Find("img, iframe, video").Length() > 0
+	case atom.Table:
+		return !isLayoutTable(node)
+	case atom.Img:
+		return attr(node, "width") != "1" && attr(node, "height") != "1"
+	case atom.Iframe, atom.Video, atom.Embed, atom.Object:
+		source := strings.ToLower(attr(node, "src") + attr(node, "data"))
+		for _, provider := range videoProviders {
+			if strings.Contains(source, provider) {
+				return true
+			}
+		}
+		return node.DataAtom == atom.Video
+	}
+	return false
+}
+
+// walk reads a node, adding its text to the current block or starting new blocks
+func (b *blockBuilder) walk(node *html.Node) {
+	switch {
+	case node.Type == html.TextNode && node.DataAtom == 0 && b.emphasis[node]:
+		b.appendEmphasis(node.Data)
+	case node.Type == html.TextNode && node.DataAtom == 0:
+		b.appendText(node.Data)
+	case node.Type == html.ElementNode || node.Type == html.TextNode:
+		// the elements replaced by their text by the cleaner keep their atom, and their children
+		b.walkElement(node)
+		return
+	}
+	b.walkChildren(node)
+}
+
+func (b *blockBuilder) walkChildren(node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		b.walk(child)
+	}
+}
+
+func (b *blockBuilder) walkElement(node *html.Node) {
+	a := node.DataAtom
+	switch {
+	case skippedAtoms[a]:
+	case headingLevels[a] > 0:
+		b.flush(types.BlockParagraph, 0)
+		b.walkChildren(node)
+		b.flush(types.BlockHeading, headingLevels[a])
+	case a == atom.Br:
+		b.lineBreak()
+	case a == atom.A:
+		if goquery.NewDocumentFromNode(node).Find("img").Length() > 0 {
+			// a linked image
+			b.walkChildren(node)
+			return
+		}
+		b.startLink(attr(node, "href"))
+		b.walkChildren(node)
+		b.endLink()
+	case styleTypes[a] != "":
+		b.startStyle(styleTypes[a])
+		b.walkChildren(node)
+		b.endStyle()
+	case inlineAtoms[a]:
+		b.walkChildren(node)
+	case a == atom.Ul || a == atom.Ol:
+		b.flush(types.BlockParagraph, 0)
+		list := types.Block{Type: types.BlockList, Ordered: a == atom.Ol}
+		list.Items = b.listItems(node, 0)
+		if len(list.Items) > 0 {
+			b.blocks = append(b.blocks, list)
+		}
+	case a == atom.Blockquote:
+		b.flush(types.BlockParagraph, 0)
+		quote := types.Block{Type: types.BlockQuote}
+		quote.Text, quote.Links, quote.Styles = b.innerText(node, "\n", nil)
+		b.appendBlock(quote)
+	case a == atom.Pre:
+		b.flush(types.BlockParagraph, 0)
+		b.appendBlock(codeBlock(node))
+	case a == atom.Table && !isLayoutTable(node):
+		b.flush(types.BlockParagraph, 0)
+		b.appendBlock(b.table(node))
+	case a == atom.Figure:
+		b.flush(types.BlockParagraph, 0)
+		b.appendBlock(b.figure(node))
+	case a == atom.Img:
+		b.flush(types.BlockParagraph, 0)
+		if image := b.imageURL(node); image != "" {
+			b.appendBlock(types.Block{Type: types.BlockFigure, URL: image, Alt: strings.TrimSpace(attr(node, "alt"))})
+		}
+	case mediaAtoms[a]:
+		b.flush(types.BlockParagraph, 0)
+		if source := b.embedURL(node); source != "" {
+			b.appendBlock(types.Block{Type: types.BlockEmbed, URL: source})
+		}
+	default:
+		// a paragraph, or a container of blocks
+		b.flush(types.BlockParagraph, 0)
+		b.walkChildren(node)
+		b.flush(types.BlockParagraph, 0)
+	}
+}
+
+// appendText adds a text to the current block, collapsing its white space
+func (b *blockBuilder) appendText(text string) {
+	for _, r := range text {
+		switch r {
+		case ' ', '\t', '\n', '\r', '\f', '\v':
+			b.space = true
+			continue
+		}
+		if b.space && b.text.Len() > 0 && !strings.HasSuffix(b.text.String(), "\n") {
+			b.text.WriteByte(' ')
+		}
+		b.space = false
+		for _, link := range b.open {
+			if link.Offset < 0 {
+				link.Offset = b.text.Len()
+			}
+		}
+		for _, style := range b.openStyles {
+			if style != nil && style.Offset < 0 {
+				style.Offset = b.text.Len()
+			}
+		}
+		b.text.WriteRune(r)
+	}
+}
+
+// appendEmphasis adds the text of an <em> element unwrapped by the cleaner, continuing the
+// previous emphasis when only spaces separate them, e.g. for the texts around a link
+func (b *blockBuilder) appendEmphasis(text string) {
+	n := len(b.styles)
+	b.startStyle(types.StyleEmphasis)
+	b.appendText(text)
+	b.endStyle()
+	if len(b.styles) == n+1 {
+		style := b.styles[n]
+		for i := n - 1; i >= 0; i-- {
+			previous := &b.styles[i]
+			if previous.Type != types.StyleEmphasis {
+				continue
+			}
+			if end := previous.Offset + len(previous.Text); end <= style.Offset &&
+				strings.TrimSpace(b.text.String()[end:style.Offset]) == "" {
+				previous.Text = b.text.String()[previous.Offset : style.Offset+len(style.Text)]
+				b.styles = b.styles[:n]
+			}
+			break
+		}
+	}
+}
+
+func (b *blockBuilder) lineBreak() {
+	if b.text.Len() > 0 && !strings.HasSuffix(b.text.String(), "\n") {
+		b.text.WriteByte('\n')
+	}
+	b.space = false
+}
+
+func (b *blockBuilder) startLink(href string) {
+	b.open = append(b.open, &types.Link{Offset: -1, URL: resolveURL(b.base, href)})
+}
+
+func (b *blockBuilder) endLink() {
+	if len(b.open) == 0 {
+		return
+	}
+	link := b.open[len(b.open)-1]
+	b.open = b.open[:len(b.open)-1]
+	if link.Offset < 0 || link.URL == "" {
+		return
+	}
+	link.Text = strings.TrimSpace(b.text.String()[link.Offset:])
+	if link.Text != "" {
+		b.links = append(b.links, *link)
+	}
+}
+
+func (b *blockBuilder) startStyle(styleType string) {
+	for _, style := range b.openStyles {
+		if style != nil && style.Type == styleType {
+			// e.g. <b> within <strong>
+			b.openStyles = append(b.openStyles, nil)
+			return
+		}
+	}
+	b.openStyles = append(b.openStyles, &types.Style{Type: styleType, Offset: -1})
+}
+
+func (b *blockBuilder) endStyle() {
+	if len(b.openStyles) == 0 {
+		return
+	}
+	style := b.openStyles[len(b.openStyles)-1]
+	b.openStyles = b.openStyles[:len(b.openStyles)-1]
+	if style == nil || style.Offset < 0 {
+		return
+	}
+	style.Text = strings.TrimSpace(b.text.String()[style.Offset:])
+	if style.Text != "" {
+		b.styles = append(b.styles, *style)
+	}
+}
+
+// flush ends the current block, which is added unless empty
+func (b *blockBuilder) flush(blockType string, level int) {
+	text := strings.TrimRight(b.text.String(), "\n")
+	b.appendBlock(types.Block{Type: blockType, Level: level, Text: text, Links: b.links, Styles: b.styles})
+	b.text.Reset()
+	b.links, b.open, b.space = nil, nil, false
+	b.styles, b.openStyles = nil, nil
+}
+
+func (b *blockBuilder) appendBlock(block types.Block) {
+	if block.Text != "" || block.URL != "" || len(block.Rows) > 0 {
+		b.blocks = append(b.blocks, block)
+	}
+}
+
+// innerText returns the text, the links and the styles of the blocks of a node, joined with the
+// separator. The nested lists are handed to the function, when given, rather than read
+func (b *blockBuilder) innerText(node *html.Node, separator string, nestedList func(*html.Node)) (string, []types.Link, []types.Style) {
+	inner := &blockBuilder{base: b.base, emphasis: b.emphasis}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if nestedList != nil && (child.DataAtom == atom.Ul || child.DataAtom == atom.Ol) {
+			nestedList(child)
+			continue
+		}
+		inner.walk(child)
+	}
+	inner.flush(types.BlockParagraph, 0)
+	var text strings.Builder
+	var links []types.Link
+	var styles []types.Style
+	for _, block := range inner.blocks {
+		blockText := strings.TrimSpace(block.Text)
+		if block.Type == types.BlockList || block.Type == types.BlockTable {
+			blockText = types.BlocksText([]types.Block{block})
+		}
+		if blockText == "" {
+			continue
+		}
+		if text.Len() > 0 {
+			text.WriteString(separator)
+		}
+		for _, link := range block.Links {
+			link.Offset += text.Len()
+			links = append(links, link)
+		}
+		for _, style := range block.Styles {
+			style.Offset += text.Len()
+			styles = append(styles, style)
+		}
+		text.WriteString(blockText)
+	}
+	return text.String(), links, styles
+}
+
+// listItems returns the items of a list and of its nested lists
+func (b *blockBuilder) listItems(list *html.Node, level int) []types.Block {
+	var items []types.Block
+	for child := list.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode && child.DataAtom == 0 {
+			continue
+		}
+		var nested []*html.Node
+		item := types.Block{Type: types.BlockListItem, Level: level, Ordered: list.DataAtom == atom.Ol}
+		item.Text, item.Links, item.Styles = b.innerText(child, " ", func(n *html.Node) { nested = append(nested, n) })
+		if child.DataAtom == atom.Ul || child.DataAtom == atom.Ol {
+			// a list nested without an item
+			nested = append(nested, child)
+			item.Text, item.Links, item.Styles = "", nil, nil
+		}
+		if item.Text != "" {
+			items = append(items, item)
+		}
+		for _, n := range nested {
+			items = append(items, b.listItems(n, level+1)...)
+		}
+	}
+	return items
+}
+
+// isLayoutTable tells whether a table lays out the page rather than holding data
+func isLayoutTable(table *html.Node) bool {
+	if strings.EqualFold(attr(table, "role"), "presentation") {
+		return true
+	}
+	return goquery.NewDocumentFromNode(table).Find(layoutTableContent).Length() > 0
+}
+
+// table returns the text of the cells of a table, along its caption
+func (b *blockBuilder) table(table *html.Node) types.Block {
+	block := types.Block{Type: types.BlockTable}
+	var rows func(*html.Node, bool)
+	rows = func(node *html.Node, inHead bool) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			switch child.DataAtom {
+			case atom.Caption:
+				block.Text, block.Links, block.Styles = b.innerText(child, " ", nil)
+			case atom.Thead:
+				rows(child, true)
+			case atom.Tbody, atom.Tfoot:
+				rows(child, false)
+			case atom.Tr:
+				var row []string
+				headers := true
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.DataAtom != atom.Td && cell.DataAtom != atom.Th {
+						continue
+					}
+					headers = headers && cell.DataAtom == atom.Th
+					text, _, _ := b.innerText(cell, " ", nil)
+					row = append(row, text)
+				}
+				if len(row) > 0 {
+					if len(block.Rows) == 0 {
+						block.Header = inHead || headers
+					}
+					block.Rows = append(block.Rows, row)
+				}
+			}
+		}
+	}
+	rows(table, false)
+	return block
+}
+
+// figure returns the image of a figure, or its embed, along its caption
+func (b *blockBuilder) figure(figure *html.Node) types.Block {
+	block := types.Block{Type: types.BlockFigure}
+	s := goquery.NewDocumentFromNode(figure).Selection
+	if img := s.Find("img").First(); img.Length() > 0 {
+		block.URL = b.imageURL(img.Get(0))
+		block.Alt = strings.TrimSpace(img.AttrOr("alt", ""))
+	} else if embed := s.Find("iframe, video, audio, embed, object").First(); embed.Length() > 0 {
+		block.Type = types.BlockEmbed
+		block.URL = b.embedURL(embed.Get(0))
+	}
+	if caption := s.Find("figcaption").First(); caption.Length() > 0 {
+		block.Text, block.Links, block.Styles = b.innerText(caption.Get(0), " ", nil)
+	}
+	return block
+}
+
+// imageURL returns the absolute URL of an image, lazy loaded ones included
+func (b *blockBuilder) imageURL(img *html.Node) string {
+	if attr(img, "width") == "1" || attr(img, "height") == "1" {
+		// a tracking pixel
+		return ""
+	}
+	for _, name := range []string{"data-src", "data-lazy-src", "data-original", "src"} {
+		if image := resolveURL(b.base, attr(img, name)); image != "" {
+			return image
+		}
+	}
+	return ""
+}
+
+// embedURL returns the absolute URL of the source of an iframe, a video or an object
+func (b *blockBuilder) embedURL(node *html.Node) string {
+	for _, name := range []string{"src", "data-src", "data"} {
+		if source := resolveURL(b.base, attr(node, name)); source != "" {
+			return source
+		}
+	}
+	source := goquery.NewDocumentFromNode(node).Find("source[src]").First().AttrOr("src", "")
+	return resolveURL(b.base, source)
+}
+
+// codeBlock returns the preformatted text of a <pre> element, with its language
+func codeBlock(pre *html.Node) types.Block {
+	s := goquery.NewDocumentFromNode(pre).Selection
+	// the preprocessing separates the tags with a space, e.g. "<pre> <code>"
+	text := strings.TrimPrefix(strings.TrimLeft(s.Text(), "\n"), " ")
+	block := types.Block{Type: types.BlockCode, Text: strings.TrimRight(strings.TrimLeft(text, "\n"), " \t\n")}
+	for _, class := range []string{attr(pre, "class"), s.Find("code").First().AttrOr("class", "")} {
+		if match := codeLanguage.FindStringSubmatch(class); match != nil {
+			block.Language = strings.ToLower(match[1])
+			break
+		}
+	}
+	return block
+}
+
+func attr(node *html.Node, name string) string {
+	for _, a := range node.Attr {
+		if a.Key == name {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// filterBlockLines removes the navigation lines and the repeated lines of the blocks of text,
+// and the blocks left empty
+func filterBlockLines(blocks []types.Block) []types.Block {
+	seen := make(map[string]bool)
+	keep := func(line string) bool {
+		if isNavigationLine(line) || seen[line] {
+			return false
+		}
+		seen[line] = true
+		return true
+	}
+	var filtered []types.Block
+	for _, block := range blocks {
+		switch block.Type {
+		case types.BlockList:
+			items := block.Items[:0]
+			for _, item := range block.Items {
+				if item = filterLines(item, keep); item.Text != "" {
+					items = append(items, item)
+				}
+			}
+			if block.Items = items; len(items) == 0 {
+				continue
+			}
+		case types.BlockCode, types.BlockTable:
+		default:
+			block = filterLines(block, keep)
+			if block.Text == "" && block.URL == "" {
+				continue
+			}
+		}
+		filtered = append(filtered, block)
+	}
+	return filtered
+}
+
+// filterLines keeps the lines of the text of a block that satisfy keep, with their links and their styles
+func filterLines(block types.Block, keep func(string) bool) types.Block {
+	var text strings.Builder
+	var links []types.Link
+	var styles []types.Style
+	offset := 0
+	for _, line := range strings.SplitAfter(block.Text, "\n") {
+		start, end := offset, offset+len(line)
+		offset = end
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || !keep(trimmed) {
+			continue
+		}
+		if text.Len() > 0 {
+			text.WriteByte('\n')
+		}
+		shift := text.Len() - start - strings.Index(line, trimmed)
+		for _, link := range block.Links {
+			if link.Offset >= start && link.Offset < end {
+				link.Offset += shift
+				links = append(links, link)
+			}
+		}
+		for _, style := range block.Styles {
+			if style.Offset >= start && style.Offset < end {
+				style.Offset += shift
+				styles = append(styles, style)
+			}
+		}
+		text.WriteString(trimmed)
+	}
+	block.Text, block.Links, block.Styles = text.String(), links, styles
+	return block
+}
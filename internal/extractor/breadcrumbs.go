@@ -0,0 +1,220 @@
+package extractor
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/advancedlogic/GoOse/internal/types"
+)
+
+// breadcrumbContainers select the breadcrumb trails marked up without metadata, by order of preference
+var breadcrumbContainers = []string{
+	"nav[aria-label#=(?i)breadcrumb], [role=navigation][aria-label#=(?i)breadcrumb]",
+	"ol.breadcrumb, ul.breadcrumb, ol.breadcrumbs, ul.breadcrumbs",
+	".breadcrumbs, .breadcrumb",
+}
+
+// maxBreadcrumbNameLength is the length, in characters, of the longest name of a breadcrumb
+const maxBreadcrumbNameLength = 100
+
+// GetBreadcrumbs returns the breadcrumb trail of the page, read from, by order of precedence:
+// the JSON-LD BreadcrumbList, the microdata or RDFa breadcrumbs, and the <nav aria-label="breadcrumb">
+// or .breadcrumb markup. It must be called before the cleaner removes the scripts and the <nav> elements
+func (extr *ContentExtractor) GetBreadcrumbs(document *goquery.Document, pageURL string) []types.Breadcrumb {
+	base, _ := url.Parse(pageURL)
+	if breadcrumbs := parseJSONLD(document).breadcrumbs(base); len(breadcrumbs) > 0 {
+		return breadcrumbs
+	}
+	for _, syntax := range []markupSyntax{microdataSyntax, rdfaSyntax} {
+		if breadcrumbs := markupBreadcrumbs(document, base, syntax); len(breadcrumbs) > 0 {
+			return breadcrumbs
+		}
+	}
+	return htmlBreadcrumbs(document, base)
+}
+
+// positionedBreadcrumb is a breadcrumb with its position in the list, if it has one
+type positionedBreadcrumb struct {
+	types.Breadcrumb
+	position int
+}
+
+// sortBreadcrumbs orders the breadcrumbs by position, keeping the document order of those without one
+func sortBreadcrumbs(positioned []positionedBreadcrumb) []types.Breadcrumb {
+	sort.SliceStable(positioned, func(i, j int) bool {
+		return positioned[i].position < positioned[j].position
+	})
+	var breadcrumbs []types.Breadcrumb
+	for _, breadcrumb := range positioned {
+		if breadcrumb.Name != "" && len([]rune(breadcrumb.Name)) <= maxBreadcrumbNameLength {
+			breadcrumbs = append(breadcrumbs, breadcrumb.Breadcrumb)
+		}
+	}
+	return breadcrumbs
+}
+
+// breadcrumbs returns the items of the first BreadcrumbList
+func (g *jsonldGraph) breadcrumbs(base *url.URL) []types.Breadcrumb {
+	for _, node := range g.nodes {
+		if !hasType(node, "BreadcrumbList") {
+			continue
+		}
+		var positioned []positionedBreadcrumb
+		elements, ok := g.resolve(node["itemListElement"]).([]interface{})
+		if !ok {
+			continue
+		}
+		for _, element := range elements {
+			listItem, ok := g.resolve(element).(map[string]interface{})
+			if !ok {
+				continue
+			}
+			breadcrumb := positionedBreadcrumb{Breadcrumb: types.Breadcrumb{Name: firstString(jsonldStrings(listItem["name"]))}}
+			switch item := g.resolve(listItem["item"]).(type) {
+			case string:
+				breadcrumb.URL = resolveURL(base, item)
+			case map[string]interface{}:
+				if breadcrumb.Name == "" {
+					breadcrumb.Name = firstString(jsonldStrings(item["name"]))
+				}
+				breadcrumb.URL = resolveURL(base, firstString(jsonldStrings(item["@id"])))
+				if breadcrumb.URL == "" {
+					breadcrumb.URL = resolveURL(base, firstString(jsonldStrings(item["url"])))
+				}
+			}
+			switch position := listItem["position"].(type) {
+			case float64:
+				breadcrumb.position = int(position)
+			case string:
+				breadcrumb.position, _ = strconv.Atoi(position)
+			}
+			positioned = append(positioned, breadcrumb)
+		}
+		if breadcrumbs := sortBreadcrumbs(positioned); len(breadcrumbs) > 0 {
+			return breadcrumbs
+		}
+	}
+	return nil
+}
+
+// markupBreadcrumbs returns the items of the first schema.org BreadcrumbList, or else the
+// data-vocabulary.org Breadcrumb items of the page
+func markupBreadcrumbs(document *goquery.Document, base *url.URL, syntax markupSyntax) []types.Breadcrumb {
+	var list *markupItem
+	var legacy []positionedBreadcrumb
+	document.Find(syntax.scope).Each(func(i int, s *goquery.Selection) {
+		for _, t := range itemTypes(s, syntax) {
+			switch {
+			case t == "BreadcrumbList" && list == nil:
+				list = readItem(s, syntax)
+			case t == "Breadcrumb":
+				item := readItem(s, syntax)
+				legacy = append(legacy, positionedBreadcrumb{Breadcrumb: types.Breadcrumb{
+					Name: cleanJSONLDText(item.first("title", "headline", "name")),
+					URL:  firstString(item.urls("url", base)),
+				}})
+			}
+		}
+	})
+	if list == nil {
+		return sortBreadcrumbs(legacy)
+	}
+	var positioned []positionedBreadcrumb
+	for _, value := range list.props["itemListElement"] {
+		if value.item == nil {
+			continue
+		}
+		listItem := value.item
+		breadcrumb := positionedBreadcrumb{Breadcrumb: types.Breadcrumb{
+			Name: cleanJSONLDText(listItem.first("name")),
+			URL:  firstString(listItem.urls("item", base)),
+		}}
+		if breadcrumb.Name == "" {
+			breadcrumb.Name = cleanJSONLDText(listItem.first("item"))
+		}
+		breadcrumb.position, _ = strconv.Atoi(listItem.first("position"))
+		positioned = append(positioned, breadcrumb)
+	}
+	return sortBreadcrumbs(positioned)
+}
+
+// htmlBreadcrumbs returns the links of the first breadcrumb trail of the page, along the
+// current page when it ends the trail without a link
+func htmlBreadcrumbs(document *goquery.Document, base *url.URL) []types.Breadcrumb {
+	var container *goquery.Selection
+	for _, selector := range breadcrumbContainers {
+		if found := document.Find(selector).First(); found.Length() > 0 {
+			container = found
+			break
+		}
+	}
+	if container == nil {
+		return nil
+	}
+	if goquery.NodeName(container) == "a" {
+		// the links themselves have the class, e.g. <a class="breadcrumb" href="...">
+		container = container.Parent()
+	}
+
+	var positioned []positionedBreadcrumb
+	items := container.Find("li")
+	if items.Length() == 0 {
+		items = container.Find("a[href], [aria-current]")
+	}
+	items.Each(func(i int, s *goquery.Selection) {
+		link := s
+		if goquery.NodeName(s) != "a" {
+			link = s.Find("a[href]").First()
+		}
+		breadcrumb := positionedBreadcrumb{Breadcrumb: types.Breadcrumb{Name: normaliseSpaces(s.Text())}}
+		if link.Length() > 0 {
+			breadcrumb.Name = normaliseSpaces(link.Text())
+			breadcrumb.URL = resolveURL(base, link.AttrOr("href", ""))
+		}
+		breadcrumb.Name = strings.Trim(breadcrumb.Name, " >»›/|")
+		positioned = append(positioned, breadcrumb)
+	})
+	return sortBreadcrumbs(positioned)
+}
+
+// GetSection returns the section of the article: its article:section, or else the
+// articleSection of its metadata, or else the last level of its breadcrumb trail
+// before the article itself
+func (extr *ContentExtractor) GetSection(article *types.Article) string {
+	if article.OpenGraph != nil && article.OpenGraph.Section != "" {
+		return article.OpenGraph.Section
+	}
+	if article.Metadata != nil && len(article.Metadata.ArticleSection) > 0 {
+		return article.Metadata.ArticleSection[0]
+	}
+	breadcrumbs := article.Breadcrumbs
+	if n := len(breadcrumbs); n > 0 && isCurrentPage(breadcrumbs[n-1], article) {
+		breadcrumbs = breadcrumbs[:n-1]
+	}
+	if len(breadcrumbs) > 0 && isHomePage(breadcrumbs[0]) {
+		breadcrumbs = breadcrumbs[1:]
+	}
+	if len(breadcrumbs) == 0 {
+		return ""
+	}
+	return breadcrumbs[len(breadcrumbs)-1].Name
+}
+
+// isCurrentPage tells whether the breadcrumb is the article: a breadcrumb without a link,
+// or a link to the page, or one named after the article
+func isCurrentPage(breadcrumb types.Breadcrumb, article *types.Article) bool {
+	return breadcrumb.URL == "" || breadcrumb.URL == article.FinalURL || breadcrumb.URL == article.CanonicalLink ||
+		strings.EqualFold(breadcrumb.Name, article.Title)
+}
+
+// isHomePage tells whether the breadcrumb links to the home page of the site
+func isHomePage(breadcrumb types.Breadcrumb) bool {
+	u, err := url.Parse(breadcrumb.URL)
+	if err != nil {
+		return false
+	}
+	return strings.Trim(u.Path, "/") == "" || strings.EqualFold(breadcrumb.Name, "home")
+}
@@ -0,0 +1,154 @@
+package extractor
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/advancedlogic/GoOse/internal/types"
+)
+
+var (
+	// doiPattern matches a DOI, with or without a "doi:" or resolver prefix
+	doiPattern = regexp.MustCompile(`\b10\.\d{4,9}/\S+`)
+	// partialDate matches the dates reduced to a year or a month, e.g. "2024" or "2024/05"
+	partialDate = regexp.MustCompile(`^(\d{4})(?:[/-](\d{1,2}))?$`)
+)
+
+// bibliographicPrism are the prism.* meta tags of the scholarly articles, unlike
+// prism.section or prism.channel which the news sites use too
+var bibliographicPrism = map[string]bool{
+	"prism.doi": true, "prism.publicationname": true, "prism.issn": true, "prism.eissn": true,
+	"prism.volume": true, "prism.number": true, "prism.startingpage": true,
+}
+
+// citationFields are the meta tags of each field, by order of preference
+var citationFields = struct {
+	title, authors, doi, journal, publisher, issn, volume, issue, firstPage, lastPage, date, pdf []string
+}{
+	title:     []string{"citation_title", "prism.title", "dc.title", "dcterms.title"},
+	authors:   []string{"citation_author", "citation_authors", "dc.creator", "dcterms.creator", "dc.contributor"},
+	doi:       []string{"citation_doi", "prism.doi", "dc.identifier", "dcterms.identifier", "citation_id"},
+	journal:   []string{"citation_journal_title", "citation_conference_title", "citation_inbook_title", "prism.publicationname", "dc.source", "dcterms.ispartof"},
+	publisher: []string{"citation_publisher", "dc.publisher", "dcterms.publisher"},
+	issn:      []string{"citation_issn", "prism.issn", "prism.eissn"},
+	volume:    []string{"citation_volume", "prism.volume"},
+	issue:     []string{"citation_issue", "prism.number"},
+	firstPage: []string{"citation_firstpage", "prism.startingpage"},
+	lastPage:  []string{"citation_lastpage", "prism.endingpage"},
+	date: []string{"citation_publication_date", "citation_date", "citation_online_date", "prism.publicationdate",
+		"dc.date.issued", "dcterms.issued", "dc.date", "dcterms.date"},
+	pdf: []string{"citation_pdf_url"},
+}
+
+// GetCitation returns the bibliographic metadata of a scholarly article, or nil when the page
+// is not one, i.e. has neither citation_* nor prism.* meta tags nor a DOI in its Dublin Core ones.
+// The Dublin Core tags of the other pages, such as the news articles, are left to the other extractors
+func (extr *ContentExtractor) GetCitation(document *goquery.Document, pageURL string) *types.Citation {
+	metas := make(map[string][]string)
+	scholarly := false
+	document.Find("meta[name][content]").Each(func(i int, s *goquery.Selection) {
+		name := strings.ToLower(strings.TrimSpace(s.AttrOr("name", "")))
+		content := strings.TrimSpace(s.AttrOr("content", ""))
+		if content == "" {
+			return
+		}
+		metas[name] = append(metas[name], content)
+		if strings.HasPrefix(name, "citation_") || bibliographicPrism[name] {
+			scholarly = true
+		}
+	})
+
+	first := func(names []string) string {
+		for _, name := range names {
+			if values := metas[name]; len(values) > 0 {
+				return cleanJSONLDText(values[0])
+			}
+		}
+		return ""
+	}
+	citation := &types.Citation{
+		Title:     first(citationFields.title),
+		Journal:   first(citationFields.journal),
+		Publisher: first(citationFields.publisher),
+		ISSN:      first(citationFields.issn),
+		Volume:    first(citationFields.volume),
+		Issue:     first(citationFields.issue),
+		FirstPage: first(citationFields.firstPage),
+		LastPage:  first(citationFields.lastPage),
+	}
+	for _, name := range citationFields.doi {
+		for _, value := range metas[name] {
+			if citation.DOI == "" {
+				citation.DOI = normaliseDOI(value)
+			}
+		}
+	}
+	if !scholarly && citation.DOI == "" {
+		return nil
+	}
+
+	for _, name := range citationFields.authors {
+		for _, value := range metas[name] {
+			if name == "citation_authors" {
+				// "Doe, Jane; Roe, John"
+				citation.Authors = append(citation.Authors, splitNonEmpty(value, ";")...)
+			} else if value = cleanJSONLDText(value); value != "" {
+				citation.Authors = append(citation.Authors, value)
+			}
+		}
+		if len(citation.Authors) > 0 {
+			break
+		}
+	}
+	for _, name := range citationFields.date {
+		for _, value := range metas[name] {
+			if citation.PublicationDate == nil {
+				citation.PublicationDate = parseCitationDate(value)
+			}
+		}
+	}
+	base, _ := url.Parse(pageURL)
+	citation.PDFURL = resolveURL(base, first(citationFields.pdf))
+	return citation
+}
+
+// normaliseDOI returns the bare DOI of an identifier such as "doi:10.1000/xyz123" or
+// "https://doi.org/10.1000/xyz123", "" when it holds none
+func normaliseDOI(identifier string) string {
+	if unescaped, err := url.PathUnescape(identifier); err == nil {
+		identifier = unescaped
+	}
+	doi := doiPattern.FindString(identifier)
+	return strings.TrimRight(doi, ".,;")
+}
+
+// parseCitationDate parses a publication date, which can be reduced to a year or a month
+func parseCitationDate(value string) *time.Time {
+	if match := partialDate.FindStringSubmatch(value); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		month := 1
+		if match[2] != "" {
+			month, _ = strconv.Atoi(match[2])
+		}
+		if month < 1 || month > 12 {
+			return nil
+		}
+		date := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	return parseDate(value)
+}
+
+func splitNonEmpty(text string, separator string) []string {
+	var parts []string
+	for _, part := range strings.Split(text, separator) {
+		if part = cleanJSONLDText(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
@@ -2,26 +2,26 @@ package extractor
 
 import (
 	"container/list"
+	"context"
 	"log"
 	"regexp"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/advancedlogic/GoOse/internal/types"
 	"golang.org/x/net/html"
 	"golang.org/x/net/html/atom"
-	"github.com/advancedlogic/GoOse/pkg/goose"
 )
 
 var whitelistedTextAtomTypes = []atom.Atom{atom.Span, atom.Em, atom.I, atom.Strong, atom.B, atom.P, atom.H1, atom.H2, atom.H3, atom.H4}
-var whitelistedExtAtomTypes = []atom.Atom{atom.A, atom.Span, atom.Em, atom.I, atom.Strong, atom.B, atom.P, atom.H1, atom.H2, atom.H3, atom.H4}
 
 // Cleaner removes menus, ads, sidebars, etc. and leaves the main content
 type Cleaner struct {
-	config goose.Configuration
+	config types.Configuration
 }
 
 // NewCleaner returns a new instance of a Cleaner
-func NewCleaner(config goose.Configuration) Cleaner {
+func NewCleaner(config types.Configuration) Cleaner {
 	return Cleaner{
 		config: config,
 	}
@@ -388,27 +388,45 @@ var removeNodesRegEx = regexp.MustCompile("" +
 
 // Clean removes HTML elements around the main content and prepares the document for parsing
 func (c *Cleaner) Clean(docToClean *goquery.Document) *goquery.Document {
+	docToClean, _ = c.CleanContext(context.Background(), docToClean)
+	return docToClean
+}
+
+// CleanContext is like Clean but stops between cleaning steps as soon as the context is done
+func (c *Cleaner) CleanContext(ctx context.Context, docToClean *goquery.Document) (*goquery.Document, error) {
 	if c.config.Debug {
 		log.Println("Starting cleaning phase with Cleaner")
 	}
-	docToClean = c.cleanBr(docToClean)
-	docToClean = c.cleanArticleTags(docToClean)
-	docToClean = c.cleanEMTags(docToClean)
-	docToClean = c.dropCaps(docToClean)
-	docToClean = c.removeScriptsStyle(docToClean)
-	docToClean = c.cleanBadTags(docToClean, keepNodesRegEx, removeNodesRegEx, &[]string{"id", "class", "name"})
-	docToClean = c.cleanBadTags(docToClean, nil, removeVisibilityStyleRegEx, &[]string{"style"})
-	docToClean = c.removeTags(docToClean, &[]string{"nav", "footer", "aside", "cite"})
-	docToClean = c.removeNavigationElements(docToClean)
-	docToClean = c.cleanParaSpans(docToClean)
-
-	docToClean = c.convertDivsToParagraphs(docToClean, "div")
-
-	docToClean = c.convertDivsToParagraphs(docToClean, "span")
-	docToClean = c.convertDivsToParagraphs(docToClean, "article")
-	docToClean = c.convertDivsToParagraphs(docToClean, "pre")
+	steps := []func(*goquery.Document) *goquery.Document{
+		c.cleanBr,
+		c.cleanArticleTags,
+		c.cleanEMTags,
+		c.dropCaps,
+		c.removeScriptsStyle,
+		func(doc *goquery.Document) *goquery.Document {
+			return c.cleanBadTags(doc, keepNodesRegEx, removeNodesRegEx, &[]string{"id", "class", "name"})
+		},
+		func(doc *goquery.Document) *goquery.Document {
+			return c.cleanBadTags(doc, nil, removeVisibilityStyleRegEx, &[]string{"style"})
+		},
+		func(doc *goquery.Document) *goquery.Document {
+			return c.removeTags(doc, &[]string{"nav", "footer", "aside", "cite"})
+		},
+		c.removeNavigationElements,
+		c.cleanParaSpans,
+		func(doc *goquery.Document) *goquery.Document { return c.convertDivsToParagraphs(doc, "div") },
+		func(doc *goquery.Document) *goquery.Document { return c.convertDivsToParagraphs(doc, "span") },
+		func(doc *goquery.Document) *goquery.Document { return c.convertDivsToParagraphs(doc, "article") },
+		func(doc *goquery.Document) *goquery.Document { return c.convertDivsToParagraphs(doc, "pre") },
+	}
+	for _, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return docToClean, err
+		}
+		docToClean = step(docToClean)
+	}
 
-	return docToClean
+	return docToClean, nil
 }
 
 func (c *Cleaner) cleanArticleTags(doc *goquery.Document) *goquery.Document {
@@ -0,0 +1,65 @@
+package extractor
+
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// minConsentArticleLength is the length of text, in characters, below which a page
+// holding a consent interstitial is considered not to include the article
+const minConsentArticleLength = 250
+
+// consentWalls are the markers of the common consent management platforms (CMP)
+var consentWalls = []struct {
+	provider string
+	selector string
+}{
+	{"OneTrust", "#onetrust-consent-sdk, #onetrust-banner-sdk"},
+	{"Sourcepoint", "iframe[id^=sp_message_iframe], div[id^=sp_message_container]"},
+	{"Quantcast", "#qc-cmp2-container, .qc-cmp2-container, #qcCmpUi"},
+	{"Didomi", "#didomi-host, #didomi-popup"},
+	{"Cookiebot", "#CybotCookiebotDialog"},
+	{"TrustArc", "#truste-consent-track, #consent_blackbar, iframe[src*='consent-pref.trustarc.com']"},
+	{"Usercentrics", "#usercentrics-root"},
+	{"Funding Choices", ".fc-consent-root"},
+	{"Yahoo", "form.consent-form, form[action*='consent.yahoo.com']"},
+	{"Google", "form[action*='consent.google.'], form[action*='consent.youtube.']"},
+	{"IAB TCF", "iframe[src*='consensu.org'], iframe[src*='/cmp/']"},
+}
+
+// consentHosts are the hosts serving nothing but consent interstitials
+var consentHosts = map[string]string{
+	"consent.yahoo.com":   "Yahoo",
+	"guce.yahoo.com":      "Yahoo",
+	"consent.google.com":  "Google",
+	"consent.youtube.com": "Google",
+}
+
+// RemoveConsentWall removes the consent banners and CMP iframes from the document, so that they
+// do not end up in the cleaned text, and returns the name of their provider ("" when none is found)
+func RemoveConsentWall(document *goquery.Document, pageURL string) string {
+	provider := ""
+	if u, err := url.Parse(pageURL); err == nil {
+		provider = consentHosts[strings.ToLower(u.Hostname())]
+	}
+	for _, wall := range consentWalls {
+		found := document.Find(wall.selector)
+		if found.Length() == 0 {
+			continue
+		}
+		if provider == "" {
+			provider = wall.provider
+		}
+		found.Remove()
+	}
+	return provider
+}
+
+// IsConsentWall tells whether a page holding a consent interstitial lacks the article,
+// i.e. whether little or no text could be extracted besides the interstitial
+func IsConsentWall(provider string, cleanedText string) bool {
+	return provider != "" && utf8.RuneCountInString(strings.TrimSpace(cleanedText)) < minConsentArticleLength
+}
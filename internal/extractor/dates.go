@@ -0,0 +1,222 @@
+package extractor
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/advancedlogic/GoOse/internal/types"
+)
+
+// Confidence of each source of dates, between 0 and 1
+var dateConfidences = map[string]float64{
+	types.DateSourceCitation:     0.95,
+	types.DateSourceJSONLD:       0.95,
+	types.DateSourceOpenGraph:    0.9,
+	types.DateSourceMicrodata:    0.85,
+	types.DateSourceRDFa:         0.85,
+	types.DateSourceMicroformats: 0.8,
+	types.DateSourceMeta:         0.8,
+	types.DateSourcePubdate:      0.75,
+	types.DateSourceTime:         0.6,
+	types.DateSourceURL:          0.5,
+	types.DateSourceText:         0.3,
+}
+
+// publishedMetas and modifiedMetas are the names of the meta tags holding the dates,
+// including the sitemap-style ones
+var (
+	publishedMetas = []string{
+		"og:published_time", "pubdate", "publishdate", "publish-date", "publish_date", "published-date",
+		"article.published", "article_date_original", "sailthru.date", "parsely-pub-date",
+		"dc.date.issued", "dcterms.issued", "dc.date", "dcterms.date", "dcterms.created", "date",
+		"publication_date", "news:publication_date", "originalpublicationdate",
+	}
+	modifiedMetas = []string{
+		"og:updated_time", "article.updated", "dc.date.modified", "dcterms.modified",
+		"last-modified", "lastmod", "modified", "revised",
+	}
+)
+
+// urlDate matches the dates in a URL path such as /2024/05/03/ or /2024-05-03-title
+var urlDate = regexp.MustCompile(`/((?:19|20)\d{2})[/-](0?[1-9]|1[0-2])[/-](0?[1-9]|[12]\d|3[01])(?:[/-]|\.html?$|$)`)
+
+// datedValue is a date along where it was found
+type datedValue struct {
+	date   *time.Time
+	source *types.DateSource
+}
+
+// ResolveDates sets the publish and modified dates of the article, and their sources, from its
+// structured sources, by order of precedence: the citation metadata (for the publish date), the
+// JSON-LD metadata, the Open Graph article:* properties, the microdata, RDFa or microformats metadata,
+// any other itemprop=datePublished/dateModified, the meta tags, the <time> elements and the URL path.
+// It must be called before the cleaner removes the scripts and the <time> elements. The dates stay
+// nil when none of these sources has one
+func (extr *ContentExtractor) ResolveDates(document *goquery.Document, article *types.Article) {
+	structured := func(metadata *time.Time, metadataSource string, og *time.Time, itemprop string, metas []string) *datedValue {
+		if metadata != nil && metadataSource == types.DateSourceJSONLD {
+			return newDatedValue(metadata, metadataSource)
+		}
+		if og != nil {
+			return newDatedValue(og, types.DateSourceOpenGraph)
+		}
+		if metadata != nil {
+			return newDatedValue(metadata, metadataSource)
+		}
+		if date := microdataDate(document, itemprop); date != nil {
+			return newDatedValue(date, types.DateSourceMicrodata)
+		}
+		if date := metaDate(document, metas); date != nil {
+			return newDatedValue(date, types.DateSourceMeta)
+		}
+		return nil
+	}
+
+	var metadata types.Metadata
+	if article.Metadata != nil {
+		metadata = *article.Metadata
+	}
+	var ogPublished, ogModified *time.Time
+	if article.OpenGraph != nil {
+		ogPublished, ogModified = article.OpenGraph.PublishedTime, article.OpenGraph.ModifiedTime
+	}
+	var published *datedValue
+	if article.Citation != nil && article.Citation.PublicationDate != nil {
+		published = newDatedValue(article.Citation.PublicationDate, types.DateSourceCitation)
+	}
+	if published == nil {
+		published = structured(metadata.DatePublished, metadata.DatePublishedSource, ogPublished, "datePublished", publishedMetas)
+	}
+	if published == nil {
+		published = timeDate(document)
+	}
+	if published == nil {
+		published = urlPathDate(article.FinalURL)
+	}
+	if published != nil {
+		article.PublishDate, article.PublishDateSource = published.date, published.source
+	}
+	if modified := structured(metadata.DateModified, metadata.DateModifiedSource, ogModified, "dateModified", modifiedMetas); modified != nil {
+		article.ModifiedDate, article.ModifiedDateSource = modified.date, modified.source
+	}
+}
+
+func newDatedValue(date *time.Time, source string) *datedValue {
+	return &datedValue{date: date, source: &types.DateSource{Name: source, Confidence: dateConfidences[source]}}
+}
+
+// microdataDate reads the date of the first element with the itemprop, from its content
+// or datetime attribute or else from its text
+func microdataDate(document *goquery.Document, itemprop string) *time.Time {
+	var date *time.Time
+	document.Find("[itemprop~=" + itemprop + "]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		for _, attr := range []string{"content", "datetime"} {
+			if value, ok := s.Attr(attr); ok {
+				date = parseDate(strings.TrimSpace(value))
+				return date == nil
+			}
+		}
+		date = parseDate(normaliseSpaces(s.Text()))
+		return date == nil
+	})
+	return date
+}
+
+// metaDate reads the date of the first meta tag named after one of the names, by order of preference
+func metaDate(document *goquery.Document, names []string) *time.Time {
+	values := make(map[string]string)
+	document.Find("meta").Each(func(i int, s *goquery.Selection) {
+		name := ""
+		for _, attr := range []string{"name", "property", "itemprop", "http-equiv"} {
+			if name, _ = s.Attr(attr); name != "" {
+				break
+			}
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, exists := values[name]; !exists {
+			values[name], _ = s.Attr("content")
+		}
+	})
+	for _, name := range names {
+		if date := parseDate(strings.TrimSpace(values[name])); date != nil {
+			return date
+		}
+	}
+	return nil
+}
+
+// timeDate reads the datetime of a <time pubdate> element, or else of the first <time>
+// of the article, or else of the first <time> of the page
+func timeDate(document *goquery.Document) *datedValue {
+	for _, candidate := range []struct {
+		selector   string
+		source     string
+		confidence float64
+	}{
+		{"time[pubdate][datetime]", types.DateSourcePubdate, dateConfidences[types.DateSourcePubdate]},
+		{"article time[datetime], header time[datetime]", types.DateSourceTime, dateConfidences[types.DateSourceTime]},
+		// any <time> of the page, e.g. in a sidebar
+		{"time[datetime]", types.DateSourceTime, 0.45},
+	} {
+		var date *time.Time
+		document.Find(candidate.selector).EachWithBreak(func(i int, s *goquery.Selection) bool {
+			value, _ := s.Attr("datetime")
+			date = parseDate(strings.TrimSpace(value))
+			return date == nil
+		})
+		if date != nil {
+			value := newDatedValue(date, candidate.source)
+			value.source.Confidence = candidate.confidence
+			return value
+		}
+	}
+	return nil
+}
+
+// urlPathDate reads the date in the path of the URL, e.g. /2024/05/03/title
+func urlPathDate(pageURL string) *datedValue {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	match := urlDate.FindStringSubmatch(u.Path)
+	if match == nil {
+		return nil
+	}
+	year, _ := strconv.Atoi(match[1])
+	month, _ := strconv.Atoi(match[2])
+	day, _ := strconv.Atoi(match[3])
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Day() != day {
+		// e.g. 2023/02/30
+		return nil
+	}
+	return newDatedValue(&date, types.DateSourceURL)
+}
+
+// TextDateSource is the source of the dates found by scanning the text of the page
+func TextDateSource() *types.DateSource {
+	return &types.DateSource{Name: types.DateSourceText, Confidence: dateConfidences[types.DateSourceText]}
+}
+
+// PageLocation returns the time zone of the page, i.e. the offset of the first structured date
+// having one, or UTC. The dates found in the text without a time zone are given this one
+func PageLocation(article *types.Article) *time.Location {
+	dates := []*time.Time{article.PublishDate, article.ModifiedDate}
+	if article.Metadata != nil {
+		dates = append(dates, article.Metadata.DatePublished, article.Metadata.DateModified)
+	}
+	if article.OpenGraph != nil {
+		dates = append(dates, article.OpenGraph.PublishedTime, article.OpenGraph.ModifiedTime)
+	}
+	for _, date := range dates {
+		if date != nil && date.Location() != time.UTC {
+			return date.Location()
+		}
+	}
+	return time.UTC
+}
@@ -2,6 +2,7 @@ package extractor
 
 import (
 	"container/list"
+	"context"
 	"log"
 	"math"
 	"net/url"
@@ -18,8 +19,8 @@ import (
 	"golang.org/x/net/html"
 	"golang.org/x/net/html/atom"
 
+	"github.com/advancedlogic/GoOse/internal/types"
 	"github.com/advancedlogic/GoOse/internal/utils"
-	"github.com/advancedlogic/GoOse/pkg/goose"
 )
 
 const defaultLanguage = "en"
@@ -36,6 +37,9 @@ var titleDelimiters = []string{
 	":",
 }
 
+// relativeDateContexts are the elements whose relative dates, e.g. "2 hours ago", date the article
+var relativeDateContexts = "time, header, address, [class*=byline], [class*=Byline], [class*=dateline], [rel=author]"
+
 var aRelTagSelector = "a[rel=tag]"
 var aHrefTagSelector = [...]string{"/tag/", "/tags/", "/topic/", "?keyword"}
 
@@ -43,18 +47,18 @@ var aHrefTagSelector = [...]string{"/tag/", "/tags/", "/topic/", "?keyword"}
 
 // ContentExtractor can parse the HTML and fetch various properties
 type ContentExtractor struct {
-	config goose.Configuration
+	config types.Configuration
 }
 
 // NewExtractor returns a configured HTML parser
-func NewExtractor(config goose.Configuration) ContentExtractor {
+func NewExtractor(config types.Configuration) ContentExtractor {
 	return ContentExtractor{
 		config: config,
 	}
 }
 
-// if the article has a title set in the source, use that
-func (extr *ContentExtractor) getTitleUnmodified(document *goquery.Document) string {
+// GetTitleUnmodified returns the title set in the source as is, if the article has one
+func (extr *ContentExtractor) GetTitleUnmodified(document *goquery.Document) string {
 	title := ""
 
 	titleElement := document.Find("title")
@@ -111,7 +115,7 @@ func (extr *ContentExtractor) GetTitleFromUnmodifiedTitle(title string) string {
 
 // GetTitle returns the title set in the source, if the article has one
 func (extr *ContentExtractor) GetTitle(document *goquery.Document) string {
-	title := extr.getTitleUnmodified(document)
+	title := extr.GetTitleUnmodified(document)
 	if extr.config.Debug {
 		log.Printf("Unmodified title: %q\n", title)
 	}
@@ -159,7 +163,23 @@ func (extr *ContentExtractor) splitTitle(titles []string) string {
 
 // GetMetaLanguage returns the meta language set in the source, if the article has one
 func (extr *ContentExtractor) GetMetaLanguage(document *goquery.Document) string {
-	var language string
+	language := declaredLanguage(document)
+	_, ok := utils.Sw[language]
+
+	if language == "" || !ok {
+		language = extr.config.StopWords.SimpleLanguageDetector(document.Find("html").Text())
+		if language == "" {
+			language = defaultLanguage
+		}
+	}
+
+	extr.config.TargetLanguage = language
+	return language
+}
+
+// declaredLanguage returns the primary language declared by the lang attribute or the
+// Content-Language of the page, e.g. "pt" for "pt-BR"
+func declaredLanguage(document *goquery.Document) string {
 	shtml := document.Find("html")
 	attr, _ := shtml.Attr("lang")
 	if attr == "" {
@@ -180,22 +200,9 @@ func (extr *ContentExtractor) GetMetaLanguage(document *goquery.Document) string
 	}
 	idx := strings.LastIndex(attr, "-")
 	if idx == -1 {
-		language = attr
-	} else {
-		language = attr[0:idx]
-	}
-
-	_, ok := utils.Sw[language]
-
-	if language == "" || !ok {
-		language = extr.config.StopWords.SimpleLanguageDetector(shtml.Text())
-		if language == "" {
-			language = defaultLanguage
-		}
+		return attr
 	}
-
-	extr.config.TargetLanguage = language
-	return language
+	return attr[0:idx]
 }
 
 // GetFavicon returns the favicon set in the source, if the article has one
@@ -325,16 +332,51 @@ func (extr *ContentExtractor) GetTags(document *goquery.Document) *set.Set {
 
 // GetPublishDate returns the publication date, if one can be located.
 func (extr *ContentExtractor) GetPublishDate(document *goquery.Document) *time.Time {
+	ts, _ := extr.GetPublishDateContext(context.Background(), document)
+	return ts
+}
+
+// GetPublishDateContext is like GetPublishDate but gives up as soon as the context is done
+func (extr *ContentExtractor) GetPublishDateContext(ctx context.Context, document *goquery.Document) (*time.Time, error) {
+	return extr.GetPublishDateAtContext(ctx, document, time.Now(), time.UTC)
+}
+
+// GetPublishDateAtContext is like GetPublishDateContext for a page fetched at the given time,
+// whose dates without a time zone are in loc. The absolute dates are looked for in the language
+// of the page (see GetMetaLanguage), and then in the English formats known to dateparse. The
+// relative ones, such as "2 hours ago" or "today", are resolved against the fetch time, and only
+// taken from the bylines, the headers and the <time> elements when the page has no absolute date.
+// It only fails when the context is done
+func (extr *ContentExtractor) GetPublishDateAtContext(ctx context.Context, document *goquery.Document, fetchTime time.Time, loc *time.Location) (*time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// the text is the last resort for the date, whose absence is no reason to fail
 	raw, err := document.Html()
 	if err != nil {
 		log.Printf("Error converting document HTML nodes to raw HTML: %s (publish date detection aborted)\n", err)
-		return nil
+		return nil, nil
 	}
 
 	text, err := html2text.FromString(raw)
 	if err != nil {
 		log.Printf("Error converting document HTML to plaintext: %s (publish date detection aborted)\n", err)
-		return nil
+		return nil, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	language := extr.config.TargetLanguage
+	if declared := strings.ToLower(declaredLanguage(document)); utils.IsDateLanguage(declared) {
+		// the dates are written in a few more languages than the stop words
+		language = declared
+	}
+	parser := utils.NewDateParser(language, fetchTime, loc)
+	if date := parser.Find(text); date != nil {
+		return date, nil
 	}
 
 	text = strings.ToLower(text)
@@ -370,6 +412,9 @@ func (extr *ContentExtractor) GetPublishDate(document *goquery.Document) *time.T
 	)
 	for _, n := range []int{3, 4, 5, 2, 6} {
 		for _, win := range window.Rolling(tuple1, n) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
 			if !expr.MatchString(strings.Join(win, " ")) {
 				continue
 			}
@@ -396,16 +441,39 @@ func (extr *ContentExtractor) GetPublishDate(document *goquery.Document) *time.T
 	}
 
 	if found {
-		return &ts
+		return &ts, nil
 	}
-	return nil
+
+	var bylines []string
+	document.Find(relativeDateContexts).Each(func(i int, s *goquery.Selection) {
+		bylines = append(bylines, s.Text())
+	})
+	return parser.FindRelative(strings.Join(bylines, "\n")), nil
 }
 
 // GetCleanTextAndLinks parses the main HTML node for text and links
 func (extr *ContentExtractor) GetCleanTextAndLinks(topNode *goquery.Selection, lang string) (string, []string) {
+	text, links, _ := extr.GetCleanTextAndLinksContext(context.Background(), topNode, lang)
+	return text, links
+}
+
+// GetCleanTextAndLinksContext is like GetCleanTextAndLinks but gives up as soon as the context is done
+func (extr *ContentExtractor) GetCleanTextAndLinksContext(ctx context.Context, topNode *goquery.Selection, lang string) (string, []string, error) {
+	blocks, links, err := extr.GetBlocksAndLinksContext(ctx, topNode, lang, "", nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return types.BlocksCleanedText(blocks), links, nil
+}
+
+// GetBlocksAndLinksContext parses the main HTML node for the blocks of the content and the links,
+// the URLs of the links of the blocks resolved against the one of the page. The emphasis are the
+// text nodes returned by EmphasisNodes
+func (extr *ContentExtractor) GetBlocksAndLinksContext(ctx context.Context, topNode *goquery.Selection, lang string, pageURL string, emphasis map[*html.Node]bool) ([]types.Block, []string, error) {
 	outputFormatter := new(outputFormatter)
 	outputFormatter.config = extr.config
-	return outputFormatter.getFormattedText(topNode, lang)
+	outputFormatter.structured = extr.isStructuredContent
+	return outputFormatter.getFormattedBlocks(ctx, topNode, lang, pageURL, emphasis)
 }
 
 // CalculateBestNode checks for the HTML node most likely to contain the main content.
@@ -413,9 +481,20 @@ func (extr *ContentExtractor) GetCleanTextAndLinks(topNode *goquery.Selection, l
 // and the number of consecutive paragraphs together, which should form the cluster of text that this node is around
 // also store on how high up the paragraphs are, comments are usually at the bottom and should get a lower score
 func (extr *ContentExtractor) CalculateBestNode(document *goquery.Document) *goquery.Selection {
+	topNode, _ := extr.CalculateBestNodeContext(context.Background(), document)
+	return topNode
+}
+
+// CalculateBestNodeContext is like CalculateBestNode but gives up as soon as the context is done.
+// It returns ErrNoContent when no node qualifies
+func (extr *ContentExtractor) CalculateBestNodeContext(ctx context.Context, document *goquery.Document) (*goquery.Selection, error) {
 	// First try site-specific selectors for known news sites
-	if siteSpecificNode := extr.tryNewsSelectors(document); siteSpecificNode != nil {
-		return siteSpecificNode
+	siteSpecificNode, err := extr.tryNewsSelectors(ctx, document)
+	if err != nil {
+		return nil, err
+	}
+	if siteSpecificNode != nil {
+		return siteSpecificNode, nil
 	}
 	
 	var topNode *goquery.Selection
@@ -429,6 +508,9 @@ func (extr *ContentExtractor) CalculateBestNode(document *goquery.Document) *goq
 	parentNodes := set.New(set.ThreadSafe).(*set.Set)
 	nodesWithText := list.New()
 	for _, node := range nodesToCheck {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		textNode := node.Text()
 		ws := extr.config.StopWords.StopWordsCount(extr.config.TargetLanguage, textNode)
 		highLinkDensity := extr.isHighLinkDensity(node)
@@ -450,6 +532,9 @@ func (extr *ContentExtractor) CalculateBestNode(document *goquery.Document) *goq
 	}
 
 	for n := nodesWithText.Front(); n != nil; n = n.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		node := n.Value.(*goquery.Selection)
 		boostScore := 0.0
 		if extr.isBoostable(node) {
@@ -510,7 +595,10 @@ func (extr *ContentExtractor) CalculateBestNode(document *goquery.Document) *goq
 			topNode = e
 		}
 	}
-	return topNode
+	if topNode == nil {
+		return nil, types.ErrNoContent
+	}
+	return topNode, nil
 }
 
 // returns the gravityScore as an integer from this node
@@ -707,7 +795,7 @@ func (extr *ContentExtractor) getArticleContentBoost(node *goquery.Selection) in
 }
 
 // tryNewsSelectors attempts to find article content using site-specific selectors
-func (extr *ContentExtractor) tryNewsSelectors(document *goquery.Document) *goquery.Selection {
+func (extr *ContentExtractor) tryNewsSelectors(ctx context.Context, document *goquery.Document) (*goquery.Selection, error) {
 	// Common article content selectors used by major news sites
 	selectors := []string{
 		".article__content", // CNN and other major news sites
@@ -733,6 +821,9 @@ func (extr *ContentExtractor) tryNewsSelectors(document *goquery.Document) *goqu
 	}
 	
 	for _, selector := range selectors {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		selection := document.Find(selector)
 		if selection.Length() > 0 {
 			// Validate that this looks like article content
@@ -748,7 +839,7 @@ func (extr *ContentExtractor) tryNewsSelectors(document *goquery.Document) *goqu
 								selector, len(text), paragraphs.Length())
 						}
 						// Extract only the paragraph content, not the entire container
-						return extr.extractParagraphContent(selection)
+						return extr.extractParagraphContent(selection), nil
 					}
 				}
 			}
@@ -759,7 +850,10 @@ func (extr *ContentExtractor) tryNewsSelectors(document *goquery.Document) *goqu
 	var bestCandidate *goquery.Selection
 	var bestScore int
 	
-	document.Find("div, article, section").Each(func(i int, s *goquery.Selection) {
+	document.Find("div, article, section").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if ctx.Err() != nil {
+			return false
+		}
 		class, _ := s.Attr("class")
 		id, _ := s.Attr("id")
 		
@@ -790,9 +884,13 @@ func (extr *ContentExtractor) tryNewsSelectors(document *goquery.Document) *goqu
 				}
 			}
 		}
+		return true
 	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	
-	return bestCandidate
+	return bestCandidate, nil
 }
 
 // hasGoodContentSignals checks if a node contains signals that indicate it's article content
@@ -1090,6 +1188,10 @@ func (extr *ContentExtractor) PostCleanup(targetNode *goquery.Selection) *goquer
 				extr.config.Parser.RemoveNode(s)
 				return
 			}
+			// the blocks keep the headings, the lists, the tables and the media
+			if extr.isStructuredContent(s.Get(0)) {
+				return
+			}
 
 			subParagraph := s.Find("p")
 			subParagraph.Each(func(j int, e *goquery.Selection) {
@@ -0,0 +1,114 @@
+package extractor
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/advancedlogic/GoOse/internal/types"
+	"github.com/pkg/errors"
+)
+
+// iconRels map the rel tokens of the icon links to the IconRel* constants
+var iconRels = map[string]string{
+	"icon":                         types.IconRelIcon,
+	"apple-touch-icon":             types.IconRelAppleTouchIcon,
+	"apple-touch-icon-precomposed": types.IconRelAppleTouchIcon,
+	"mask-icon":                    types.IconRelMaskIcon,
+}
+
+// webManifest is the part of a web app manifest (https://www.w3.org/TR/appmanifest/) listing its icons
+type webManifest struct {
+	Icons []struct {
+		Src     string `json:"src"`
+		Sizes   string `json:"sizes"`
+		Type    string `json:"type"`
+		Purpose string `json:"purpose"`
+	} `json:"icons"`
+}
+
+// GetIcons returns the icons declared by the <link> tags of the page (icon, shortcut icon,
+// apple-touch-icon and mask-icon), in document order, with their absolute URL and declared sizes
+func (extr *ContentExtractor) GetIcons(document *goquery.Document, pageURL string) []types.Icon {
+	base, _ := url.Parse(pageURL)
+	var icons []types.Icon
+	seen := make(map[string]bool)
+	document.Find("link[rel][href]").Each(func(i int, s *goquery.Selection) {
+		rel := ""
+		for _, token := range strings.Fields(strings.ToLower(s.AttrOr("rel", ""))) {
+			if r, ok := iconRels[token]; ok {
+				rel = r
+				break
+			}
+		}
+		href := resolveURL(base, s.AttrOr("href", ""))
+		if rel == "" || href == "" || seen[rel+" "+href] {
+			return
+		}
+		seen[rel+" "+href] = true
+		icons = append(icons, newIcon(href, rel, s.AttrOr("type", ""), s.AttrOr("sizes", ""), ""))
+	})
+	return icons
+}
+
+// GetManifestURL returns the absolute URL of the web app manifest linked by the page, if any
+func (extr *ContentExtractor) GetManifestURL(document *goquery.Document, pageURL string) string {
+	base, _ := url.Parse(pageURL)
+	manifest := ""
+	document.Find("link[rel][href]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		for _, token := range strings.Fields(strings.ToLower(s.AttrOr("rel", ""))) {
+			if token == "manifest" {
+				manifest = resolveURL(base, s.AttrOr("href", ""))
+				return manifest == ""
+			}
+		}
+		return true
+	})
+	return manifest
+}
+
+// ParseManifestIcons returns the icons listed by a web app manifest, their URL resolved
+// against the one of the manifest
+func ParseManifestIcons(data []byte, manifestURL string) ([]types.Icon, error) {
+	var manifest webManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, errors.Wrap(err, "could not parse the web app manifest "+manifestURL)
+	}
+	base, _ := url.Parse(manifestURL)
+	var icons []types.Icon
+	for _, entry := range manifest.Icons {
+		if src := resolveURL(base, entry.Src); src != "" {
+			icons = append(icons, newIcon(src, types.IconRelManifest, entry.Type, entry.Sizes, strings.ToLower(entry.Purpose)))
+		}
+	}
+	return icons, nil
+}
+
+// newIcon returns an icon with its sizes parsed, e.g. sizes="16x16 32x32" or sizes="any"
+func newIcon(href string, rel string, mediaType string, sizes string, purpose string) types.Icon {
+	icon := types.Icon{URL: href, Rel: rel, Type: strings.TrimSpace(mediaType), Purpose: strings.TrimSpace(purpose)}
+	for _, size := range strings.Fields(strings.ToLower(sizes)) {
+		if size == "any" {
+			icon.Scalable = true
+			continue
+		}
+		dimensions := strings.Split(size, "x")
+		if len(dimensions) != 2 {
+			continue
+		}
+		width, errWidth := strconv.Atoi(dimensions[0])
+		height, errHeight := strconv.Atoi(dimensions[1])
+		if errWidth == nil && errHeight == nil && width > 0 && height > 0 {
+			icon.Sizes = append(icon.Sizes, types.IconSize{Width: width, Height: height})
+		}
+	}
+	if u, err := url.Parse(href); err == nil && strings.HasSuffix(strings.ToLower(u.Path), ".svg") {
+		icon.Scalable = true
+	}
+	if rel == types.IconRelMaskIcon || strings.EqualFold(icon.Type, "image/svg+xml") {
+		icon.Scalable = true
+	}
+	return icon
+}
@@ -0,0 +1,323 @@
+package extractor
+
+import (
+	"encoding/json"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/advancedlogic/GoOse/internal/types"
+	"github.com/araddon/dateparse"
+)
+
+// articleTypes are the schema.org types describing an article, by order of preference
+var articleTypes = []string{
+	"NewsArticle", "ReportageNewsArticle", "AnalysisNewsArticle", "OpinionNewsArticle",
+	"BackgroundNewsArticle", "ReviewNewsArticle", "Article", "BlogPosting", "LiveBlogPosting",
+	"TechArticle", "ScholarlyArticle", "Report", "SocialMediaPosting",
+}
+
+var (
+	trailingCommas = regexp.MustCompile(`,\s*([\]}])`)
+	asciiSpaces    = regexp.MustCompile(`[ \t\r\n\f]+`)
+)
+
+// jsonldGraph holds the objects of every JSON-LD script of a page, flattened,
+// along an index of the objects having an @id
+type jsonldGraph struct {
+	nodes []map[string]interface{}
+	ids   map[string]map[string]interface{}
+}
+
+// GetJSONLD returns the schema.org metadata of the article found in the JSON-LD scripts
+// of the document, or nil when there is none. It must be called before the cleaner removes the scripts
+func (extr *ContentExtractor) GetJSONLD(document *goquery.Document, pageURL string) *types.Metadata {
+	graph := parseJSONLD(document)
+	node := graph.article()
+	if node == nil {
+		return nil
+	}
+
+	base, _ := url.Parse(pageURL)
+	metadata := &types.Metadata{
+		Type:           jsonldType(node),
+		Headline:       firstString(jsonldStrings(node["headline"])),
+		DatePublished:  jsonldDate(node["datePublished"]),
+		DateModified:   jsonldDate(node["dateModified"]),
+		ArticleSection: jsonldStrings(node["articleSection"]),
+		Keywords:       jsonldKeywords(node["keywords"]),
+		Sources:        []string{types.MetadataSourceJSONLD},
+	}
+	if metadata.DatePublished != nil {
+		metadata.DatePublishedSource = types.DateSourceJSONLD
+	}
+	if metadata.DateModified != nil {
+		metadata.DateModifiedSource = types.DateSourceJSONLD
+	}
+	if metadata.Headline == "" {
+		metadata.Headline = firstString(jsonldStrings(node["name"]))
+	}
+	for _, author := range graph.entities(node["author"], base) {
+		if author.Name != "" {
+			metadata.Authors = append(metadata.Authors, author)
+		}
+	}
+	if publishers := graph.entities(node["publisher"], base); len(publishers) > 0 {
+		metadata.Publisher = &publishers[0]
+	}
+	if images := graph.images(node["image"], base); len(images) > 0 {
+		metadata.Image = images[0]
+	} else if images := graph.images(node["thumbnailUrl"], base); len(images) > 0 {
+		metadata.Image = images[0]
+	}
+	switch free := node["isAccessibleForFree"].(type) {
+	case bool:
+		metadata.IsAccessibleForFree = &free
+	case string:
+		value := strings.EqualFold(free, "true")
+		if value || strings.EqualFold(free, "false") {
+			metadata.IsAccessibleForFree = &value
+		}
+	}
+	return metadata
+}
+
+// parseJSONLD decodes the JSON-LD scripts of the document, tolerating the raw newlines
+// and the trailing commas that many sites leave in them
+func parseJSONLD(document *goquery.Document) *jsonldGraph {
+	graph := &jsonldGraph{ids: make(map[string]map[string]interface{})}
+	document.Find(`script[type="application/ld+json"]`).Each(func(i int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		text = strings.TrimPrefix(strings.TrimSuffix(text, "-->"), "<!--")
+		text = strings.TrimPrefix(strings.TrimSuffix(strings.TrimSpace(text), "//]]>"), "//<![CDATA[")
+		var value interface{}
+		if err := json.Unmarshal([]byte(text), &value); err != nil {
+			text = strings.NewReplacer("\n", " ", "\r", " ", "\t", " ").Replace(text)
+			text = trailingCommas.ReplaceAllString(text, "$1")
+			if err := json.Unmarshal([]byte(text), &value); err != nil {
+				return
+			}
+		}
+		graph.add(value)
+	})
+	return graph
+}
+
+// add flattens the arrays and the @graph objects
+func (g *jsonldGraph) add(value interface{}) {
+	switch value := value.(type) {
+	case []interface{}:
+		for _, item := range value {
+			g.add(item)
+		}
+	case map[string]interface{}:
+		if nested, ok := value["@graph"]; ok {
+			g.add(nested)
+		}
+		if _, ok := value["@type"]; !ok {
+			return
+		}
+		g.nodes = append(g.nodes, value)
+		if id, ok := value["@id"].(string); ok && id != "" {
+			if _, exists := g.ids[id]; !exists {
+				g.ids[id] = value
+			}
+		}
+	}
+}
+
+// article returns the object describing the article, looking into the mainEntity of the web pages too
+func (g *jsonldGraph) article() map[string]interface{} {
+	candidates := append([]map[string]interface{}{}, g.nodes...)
+	for _, node := range g.nodes {
+		if main, ok := g.resolve(node["mainEntity"]).(map[string]interface{}); ok {
+			candidates = append(candidates, main)
+		}
+	}
+	for _, articleType := range articleTypes {
+		for _, node := range candidates {
+			if hasType(node, articleType) {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+// resolve replaces a reference such as {"@id": "#author"} by the object it refers to
+func (g *jsonldGraph) resolve(value interface{}) interface{} {
+	if object, ok := value.(map[string]interface{}); ok {
+		if id, ok := object["@id"].(string); ok && len(object) == 1 {
+			if referenced, ok := g.ids[id]; ok {
+				return referenced
+			}
+		}
+	}
+	return value
+}
+
+// entities returns the persons or organizations of a value, which can be a
+// name, an object, a reference or an array of them
+func (g *jsonldGraph) entities(value interface{}, base *url.URL) []types.Entity {
+	var entities []types.Entity
+	switch value := g.resolve(value).(type) {
+	case []interface{}:
+		for _, item := range value {
+			entities = append(entities, g.entities(item, base)...)
+		}
+	case string:
+		if name := cleanJSONLDText(value); name != "" {
+			entities = append(entities, types.Entity{Name: name})
+		}
+	case map[string]interface{}:
+		entity := types.Entity{
+			Type: jsonldType(value),
+			Name: firstString(jsonldStrings(value["name"])),
+			URL:  resolveURL(base, firstString(jsonldStrings(value["url"]))),
+		}
+		if entity.URL == "" {
+			if sameAs := jsonldStrings(value["sameAs"]); len(sameAs) > 0 {
+				entity.URL = resolveURL(base, sameAs[0])
+			}
+		}
+		if logos := g.images(value["logo"], base); len(logos) > 0 {
+			entity.Logo = logos[0]
+		}
+		if entity.Name != "" || entity.URL != "" {
+			entities = append(entities, entity)
+		}
+	}
+	return entities
+}
+
+// images returns the absolute URLs of a value holding URLs or ImageObjects
+func (g *jsonldGraph) images(value interface{}, base *url.URL) []string {
+	var images []string
+	switch value := g.resolve(value).(type) {
+	case []interface{}:
+		for _, item := range value {
+			images = append(images, g.images(item, base)...)
+		}
+	case string:
+		if image := resolveURL(base, value); image != "" {
+			images = append(images, image)
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"url", "contentUrl"} {
+			if image := resolveURL(base, firstString(jsonldStrings(value[key]))); image != "" {
+				images = append(images, image)
+				break
+			}
+		}
+	}
+	return images
+}
+
+// jsonldStrings returns the texts of a value, which can be a string, a
+// {"@value": ...} object or an array of them
+func jsonldStrings(value interface{}) []string {
+	var texts []string
+	switch value := value.(type) {
+	case string:
+		if text := cleanJSONLDText(value); text != "" {
+			texts = append(texts, text)
+		}
+	case []interface{}:
+		for _, item := range value {
+			texts = append(texts, jsonldStrings(item)...)
+		}
+	case map[string]interface{}:
+		texts = jsonldStrings(value["@value"])
+	}
+	return texts
+}
+
+// jsonldKeywords splits the comma-separated keywords
+func jsonldKeywords(value interface{}) []string {
+	var keywords []string
+	for _, text := range jsonldStrings(value) {
+		for _, keyword := range strings.Split(text, ",") {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				keywords = append(keywords, keyword)
+			}
+		}
+	}
+	return keywords
+}
+
+// jsonldDate parses a date, keeping its time zone offset
+func jsonldDate(value interface{}) *time.Time {
+	return parseDate(firstString(jsonldStrings(value)))
+}
+
+// parseDate parses a date of the metadata, ISO 8601 most of the time, keeping its time zone offset
+func parseDate(text string) *time.Time {
+	if text == "" {
+		return nil
+	}
+	date, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		if date, err = dateparse.ParseAny(text); err != nil {
+			return nil
+		}
+	}
+	return &date
+}
+
+// jsonldType returns the (first) schema.org type of an object, without its vocabulary prefix
+func jsonldType(node map[string]interface{}) string {
+	names := jsonldStrings(node["@type"])
+	if len(names) == 0 {
+		return ""
+	}
+	return schemaName(names[0])
+}
+
+func hasType(node map[string]interface{}, name string) bool {
+	for _, t := range jsonldStrings(node["@type"]) {
+		if schemaName(t) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaName strips the vocabulary from a type, e.g. "http://schema.org/Article"
+func schemaName(t string) string {
+	return t[strings.LastIndexAny(t, "/:")+1:]
+}
+
+// cleanJSONLDText unescapes the HTML entities and collapses the ASCII white space,
+// keeping the ideographic spaces of the CJK titles
+func cleanJSONLDText(text string) string {
+	return strings.TrimSpace(asciiSpaces.ReplaceAllString(html.UnescapeString(text), " "))
+}
+
+func firstString(texts []string) string {
+	if len(texts) == 0 {
+		return ""
+	}
+	return texts[0]
+}
+
+// resolveURL returns the absolute form of a URL found in the page, "" when it is not a web URL
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
@@ -0,0 +1,282 @@
+package extractor
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/advancedlogic/GoOse/internal/types"
+)
+
+// markupItem is an item of the microdata, RDFa or microformats of a page: its types
+// and the values of its properties, which are texts or nested items
+type markupItem struct {
+	types []string
+	props map[string][]markupValue
+}
+
+// markupValue is the value of a property: its text, and its URL for the links and the media
+type markupValue struct {
+	text string
+	url  string
+	item *markupItem
+}
+
+// markupSyntax tells how the items of a syntax are marked up
+type markupSyntax struct {
+	source       string // MetadataSource*
+	scope        string // selector of the elements starting an item
+	typeAttr     string
+	propertyAttr string
+}
+
+var (
+	microdataSyntax = markupSyntax{types.MetadataSourceMicrodata, "[itemscope]", "itemtype", "itemprop"}
+	rdfaSyntax      = markupSyntax{types.MetadataSourceRDFa, "[typeof]", "typeof", "property"}
+)
+
+// propertyAliases map the Dublin Core properties, often used along RDFa, to the schema.org ones
+var propertyAliases = map[string]string{
+	"title":    "headline",
+	"creator":  "author",
+	"created":  "datePublished",
+	"issued":   "datePublished",
+	"date":     "datePublished",
+	"modified": "dateModified",
+	"subject":  "keywords",
+}
+
+// GetMicrodata returns the metadata of the article found in the microdata (itemscope/itemprop)
+// of the document, or nil when there is none. It must be called before the cleaner removes the markup
+func (extr *ContentExtractor) GetMicrodata(document *goquery.Document, pageURL string) *types.Metadata {
+	return markupMetadata(document, pageURL, microdataSyntax)
+}
+
+// GetRDFa returns the metadata of the article found in the RDFa (typeof/property) of the document,
+// or nil when there is none. It must be called before the cleaner removes the markup
+func (extr *ContentExtractor) GetRDFa(document *goquery.Document, pageURL string) *types.Metadata {
+	return markupMetadata(document, pageURL, rdfaSyntax)
+}
+
+// markupMetadata reads the item of the article. Pages with several articles, such as
+// the home page of a blog, have no metadata
+func markupMetadata(document *goquery.Document, pageURL string, syntax markupSyntax) *types.Metadata {
+	var articles []*goquery.Selection
+	document.Find(syntax.scope).Each(func(i int, s *goquery.Selection) {
+		for _, t := range itemTypes(s, syntax) {
+			if isArticleType(t) {
+				articles = append(articles, s)
+				return
+			}
+		}
+	})
+	if len(articles) != 1 {
+		return nil
+	}
+	base, _ := url.Parse(pageURL)
+	return itemMetadata(readItem(articles[0], syntax), base, syntax.source)
+}
+
+// readItem reads the properties of the item started by the element, leaving aside those of its nested items
+func readItem(scope *goquery.Selection, syntax markupSyntax) *markupItem {
+	item := &markupItem{types: itemTypes(scope, syntax), props: make(map[string][]markupValue)}
+	scope.Find("[" + syntax.propertyAttr + "]").Each(func(i int, s *goquery.Selection) {
+		if s.ParentsFiltered(syntax.scope).First().Get(0) != scope.Get(0) {
+			return
+		}
+		value := markupValue{text: markupText(s), url: markupURL(s)}
+		if s.Is(syntax.scope) {
+			value = markupValue{item: readItem(s, syntax)}
+		}
+		names, _ := s.Attr(syntax.propertyAttr)
+		added := make(map[string]bool)
+		for _, name := range strings.Fields(names) {
+			name = schemaName(name)
+			if alias, ok := propertyAliases[name]; ok {
+				name = alias
+			}
+			if !added[name] {
+				// e.g. itemprop="author creator"
+				added[name] = true
+				item.props[name] = append(item.props[name], value)
+			}
+		}
+	})
+	return item
+}
+
+// itemTypes returns the types of an item, without their vocabulary prefix
+func itemTypes(s *goquery.Selection, syntax markupSyntax) []string {
+	value, _ := s.Attr(syntax.typeAttr)
+	var names []string
+	for _, t := range strings.Fields(value) {
+		names = append(names, schemaName(t))
+	}
+	return names
+}
+
+func isArticleType(t string) bool {
+	for _, articleType := range articleTypes {
+		if t == articleType {
+			return true
+		}
+	}
+	return false
+}
+
+// markupText returns the text of a property element: its content attribute,
+// its datetime or value, or else its text
+func markupText(s *goquery.Selection) string {
+	if content, ok := s.Attr("content"); ok {
+		return strings.TrimSpace(content)
+	}
+	attrs := map[string]string{"time": "datetime", "data": "value", "meter": "value"}
+	if attr, ok := attrs[goquery.NodeName(s)]; ok {
+		if value, ok := s.Attr(attr); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return normaliseSpaces(s.Text())
+}
+
+// markupURL returns the URL of a property element, i.e. of the links and the
+// media, or else its content or resource attribute
+func markupURL(s *goquery.Selection) string {
+	attrs := map[string]string{
+		"a": "href", "area": "href", "link": "href",
+		"img": "src", "audio": "src", "video": "src", "source": "src", "iframe": "src", "embed": "src", "track": "src",
+		"object": "data",
+	}
+	if attr, ok := attrs[goquery.NodeName(s)]; ok {
+		if value, ok := s.Attr(attr); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	for _, attr := range []string{"content", "resource"} {
+		if value, ok := s.Attr(attr); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// texts returns the texts of the property, the names of its nested items
+func (item *markupItem) texts(name string) []string {
+	var texts []string
+	for _, value := range item.props[name] {
+		text := value.text
+		if value.item != nil {
+			text = firstString(value.item.texts("name"))
+		}
+		if text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+func (item *markupItem) first(names ...string) string {
+	for _, name := range names {
+		if text := firstString(item.texts(name)); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// urls returns the absolute URLs of the property, the url (or contentUrl) of its nested items
+func (item *markupItem) urls(name string, base *url.URL) []string {
+	var urls []string
+	for _, value := range item.props[name] {
+		if value.item != nil {
+			urls = append(urls, value.item.urls("url", base)...)
+			urls = append(urls, value.item.urls("contentUrl", base)...)
+			continue
+		}
+		ref := value.url
+		if ref == "" {
+			ref = value.text
+		}
+		if u := resolveURL(base, ref); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// entities returns the persons or organizations of the property
+func (item *markupItem) entities(name string, base *url.URL) []types.Entity {
+	var entities []types.Entity
+	for _, value := range item.props[name] {
+		entity := types.Entity{Name: cleanJSONLDText(value.text)}
+		if value.url != value.text {
+			// a link to the profile, unlike the name in the content of a <meta itemprop="author">
+			entity.URL = resolveURL(base, value.url)
+		}
+		if value.item != nil {
+			entity = types.Entity{
+				Type: firstString(value.item.types),
+				Name: cleanJSONLDText(value.item.first("name")),
+				URL:  firstString(value.item.urls("url", base)),
+				Logo: firstString(value.item.urls("logo", base)),
+			}
+		}
+		if entity.Name != "" || entity.URL != "" {
+			entities = append(entities, entity)
+		}
+	}
+	return entities
+}
+
+// itemMetadata converts an item, whose properties are named after the schema.org ones, to metadata
+func itemMetadata(item *markupItem, base *url.URL, source string) *types.Metadata {
+	metadata := &types.Metadata{
+		Type:           firstString(item.types),
+		Headline:       cleanJSONLDText(item.first("headline", "name")),
+		DatePublished:  parseDate(item.first("datePublished")),
+		DateModified:   parseDate(item.first("dateModified")),
+		ArticleSection: item.texts("articleSection"),
+		Sources:        []string{source},
+	}
+	if metadata.DatePublished != nil {
+		metadata.DatePublishedSource = source
+	}
+	if metadata.DateModified != nil {
+		metadata.DateModifiedSource = source
+	}
+	for _, author := range item.entities("author", base) {
+		if author.Type != "" {
+			if author.Name != "" {
+				metadata.Authors = append(metadata.Authors, author)
+			}
+			continue
+		}
+		// the names given as text are written like the bylines, e.g. "Jane Doe, Reuters"
+		for i, name := range splitByline(author.Name) {
+			entity := types.Entity{Name: name}
+			if i == 0 {
+				entity.URL = author.URL
+			}
+			metadata.Authors = append(metadata.Authors, entity)
+		}
+	}
+	if publishers := item.entities("publisher", base); len(publishers) > 0 {
+		metadata.Publisher = &publishers[0]
+	}
+	metadata.Image = firstString(item.urls("image", base))
+	if metadata.Image == "" {
+		metadata.Image = firstString(item.urls("thumbnailUrl", base))
+	}
+	for _, keywords := range item.texts("keywords") {
+		for _, keyword := range strings.Split(keywords, ",") {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				metadata.Keywords = append(metadata.Keywords, keyword)
+			}
+		}
+	}
+	if free := item.first("isAccessibleForFree"); strings.EqualFold(free, "true") || strings.EqualFold(free, "false") {
+		value := strings.EqualFold(free, "true")
+		metadata.IsAccessibleForFree = &value
+	}
+	return metadata
+}
@@ -0,0 +1,164 @@
+package extractor
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/advancedlogic/GoOse/internal/types"
+)
+
+// microformatRoots map the classic microformats roots to the microformats2 ones
+var microformatRoots = map[string]string{"hentry": "h-entry", "vcard": "h-card"}
+
+// microformatProperties map the classic microformats properties, per root, to the microformats2 ones
+var microformatProperties = map[string]map[string]string{
+	"h-entry": {
+		"entry-title": "p-name", "entry-summary": "p-summary", "published": "dt-published",
+		"updated": "dt-updated", "author": "p-author",
+	},
+	"h-card": {
+		"fn": "p-name", "url": "u-url", "photo": "u-photo", "logo": "u-logo", "org": "p-org",
+	},
+}
+
+// microformatNames map the microformats2 properties, per root, to the schema.org ones
+var microformatNames = map[string]map[string]string{
+	"h-entry": {
+		"name": "headline", "author": "author", "published": "datePublished", "updated": "dateModified",
+		"featured": "image", "photo": "image", "category": "keywords", "url": "url",
+	},
+	"h-card": {
+		"name": "name", "url": "url", "logo": "logo", "photo": "image",
+	},
+}
+
+// GetMicroformats returns the metadata of the article found in the h-entry of the document,
+// or in its classic hentry, or nil when there is none. It must be called before the cleaner
+// removes the markup
+func (extr *ContentExtractor) GetMicroformats(document *goquery.Document, pageURL string) *types.Metadata {
+	var entries []*goquery.Selection
+	document.Find("[class]").Each(func(i int, s *goquery.Selection) {
+		if root, _ := microformatRoot(s); root == "h-entry" {
+			entries = append(entries, s)
+		}
+	})
+	if len(entries) != 1 {
+		// e.g. the home page of a blog
+		return nil
+	}
+	base, _ := url.Parse(pageURL)
+	metadata := itemMetadata(readMicroformat(entries[0]), base, types.MetadataSourceMicroformats)
+	metadata.Type = "h-entry"
+	return metadata
+}
+
+// microformatRoot returns the root type of the element, e.g. "h-entry", and whether it
+// is a classic root (e.g. "hentry"), or "" when it is not a root
+func microformatRoot(s *goquery.Selection) (string, bool) {
+	classes := strings.Fields(s.AttrOr("class", ""))
+	for _, class := range classes {
+		if strings.HasPrefix(class, "h-") && len(class) > 2 {
+			return class, false
+		}
+	}
+	for _, class := range classes {
+		if root, ok := microformatRoots[class]; ok {
+			return root, true
+		}
+	}
+	return "", false
+}
+
+func isMicroformatRoot(s *goquery.Selection) bool {
+	root, _ := microformatRoot(s)
+	return root != ""
+}
+
+// readMicroformat reads the properties of the root element, named after the schema.org ones
+func readMicroformat(scope *goquery.Selection) *markupItem {
+	root, classic := microformatRoot(scope)
+	item := &markupItem{types: []string{root}, props: make(map[string][]markupValue)}
+	scope.Find("[class]").Each(func(i int, s *goquery.Selection) {
+		parent := s.ParentsFiltered("[class]").FilterFunction(func(j int, p *goquery.Selection) bool {
+			return isMicroformatRoot(p)
+		}).First()
+		if parent.Get(0) != scope.Get(0) {
+			return
+		}
+		for _, class := range strings.Fields(s.AttrOr("class", "")) {
+			if classic {
+				class = microformatProperties[root][class]
+			}
+			dash := strings.Index(class, "-")
+			if dash == -1 {
+				continue
+			}
+			prefix, name := class[:dash], microformatNames[root][class[dash+1:]]
+			if name == "" {
+				continue
+			}
+			var value markupValue
+			switch {
+			case isMicroformatRoot(s):
+				value.item = readMicroformat(s)
+			case prefix == "p" || prefix == "e":
+				value.text = microformatText(s)
+			case prefix == "u":
+				value.url = microformatURL(s)
+			case prefix == "dt":
+				value.text = microformatDate(s)
+			default:
+				continue
+			}
+			item.props[name] = append(item.props[name], value)
+		}
+	})
+	// the implied name and URL of a root without these properties, e.g. <a class="p-author h-card" href="...">Jane Doe</a>
+	if _, ok := item.props["name"]; !ok && root == "h-card" {
+		item.props["name"] = []markupValue{{text: microformatText(scope)}}
+	}
+	if _, ok := item.props["url"]; !ok && (goquery.NodeName(scope) == "a" || goquery.NodeName(scope) == "area") {
+		item.props["url"] = []markupValue{{url: scope.AttrOr("href", "")}}
+	}
+	return item
+}
+
+// microformatText returns the value of a p-* property
+func microformatText(s *goquery.Selection) string {
+	switch goquery.NodeName(s) {
+	case "abbr", "link":
+		if title, ok := s.Attr("title"); ok {
+			return strings.TrimSpace(title)
+		}
+	case "data", "input":
+		if value, ok := s.Attr("value"); ok {
+			return strings.TrimSpace(value)
+		}
+	case "img", "area":
+		if alt, ok := s.Attr("alt"); ok {
+			return strings.TrimSpace(alt)
+		}
+	}
+	return normaliseSpaces(s.Text())
+}
+
+// microformatURL returns the value of a u-* property
+func microformatURL(s *goquery.Selection) string {
+	for _, attr := range []string{"href", "src", "data", "poster"} {
+		if value, ok := s.Attr(attr); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return microformatText(s)
+}
+
+// microformatDate returns the value of a dt-* property
+func microformatDate(s *goquery.Selection) string {
+	for _, attr := range []string{"datetime", "title", "value"} {
+		if value, ok := s.Attr(attr); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return normaliseSpaces(s.Text())
+}
@@ -0,0 +1,138 @@
+package extractor
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/advancedlogic/GoOse/internal/types"
+)
+
+// metaProperties returns the name and the value of the <meta> tags whose name or
+// property starts with one of the prefixes, in document order
+func metaProperties(document *goquery.Document, prefixes ...string) [][2]string {
+	var properties [][2]string
+	document.Find("meta").Each(func(i int, tag *goquery.Selection) {
+		name, exists := tag.Attr("property")
+		if !exists || name == "" {
+			name, _ = tag.Attr("name")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		value, exists := tag.Attr("content")
+		if !exists {
+			value, _ = tag.Attr("value")
+		}
+		value = cleanJSONLDText(value)
+		if value == "" {
+			return
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) {
+				properties = append(properties, [2]string{name, value})
+				return
+			}
+		}
+	})
+	return properties
+}
+
+// GetOpenGraph returns the Open Graph properties of the document, or nil when there is none.
+// The URLs are resolved against the URL of the page
+func (extr *ContentExtractor) GetOpenGraph(document *goquery.Document, pageURL string) *types.OpenGraph {
+	properties := metaProperties(document, "og:", "article:")
+	if len(properties) == 0 {
+		return nil
+	}
+	base, _ := url.Parse(pageURL)
+	og := new(types.OpenGraph)
+	var image *types.OpenGraphImage
+	for _, property := range properties {
+		name, value := property[0], property[1]
+		switch name {
+		case "og:type":
+			og.Type = value
+		case "og:site_name":
+			og.SiteName = value
+		case "og:title":
+			og.Title = value
+		case "og:description":
+			og.Description = value
+		case "og:url":
+			og.URL = resolveURL(base, value)
+		case "og:locale":
+			og.Locale = value
+		case "article:published_time":
+			og.PublishedTime = parseDate(value)
+		case "article:modified_time":
+			og.ModifiedTime = parseDate(value)
+		case "article:author":
+			og.Authors = append(og.Authors, value)
+		case "article:section":
+			og.Section = value
+		case "article:tag":
+			og.Tags = append(og.Tags, value)
+		case "og:image", "og:image:url":
+			// og:image:url repeats the og:image before it, or gives the URL of an image described first
+			imageURL := resolveURL(base, value)
+			if image == nil || name == "og:image" || (image.URL != "" && image.URL != imageURL) {
+				og.Images = append(og.Images, types.OpenGraphImage{})
+				image = &og.Images[len(og.Images)-1]
+			}
+			image.URL = imageURL
+		default:
+			if !strings.HasPrefix(name, "og:image:") {
+				continue
+			}
+			if image == nil {
+				og.Images = append(og.Images, types.OpenGraphImage{})
+				image = &og.Images[len(og.Images)-1]
+			}
+			switch strings.TrimPrefix(name, "og:image:") {
+			case "secure_url":
+				image.SecureURL = resolveURL(base, value)
+			case "type":
+				image.Type = value
+			case "width":
+				image.Width, _ = strconv.Atoi(value)
+			case "height":
+				image.Height, _ = strconv.Atoi(value)
+			case "alt":
+				image.Alt = value
+			}
+		}
+	}
+	return og
+}
+
+// GetTwitterCard returns the Twitter Card properties of the document, or nil when there is none
+func (extr *ContentExtractor) GetTwitterCard(document *goquery.Document, pageURL string) *types.TwitterCard {
+	properties := metaProperties(document, "twitter:")
+	if len(properties) == 0 {
+		return nil
+	}
+	base, _ := url.Parse(pageURL)
+	card := new(types.TwitterCard)
+	for _, property := range properties {
+		name, value := property[0], property[1]
+		switch name {
+		case "twitter:card":
+			card.Card = value
+		case "twitter:site":
+			card.Site = value
+		case "twitter:creator":
+			card.Creator = value
+		case "twitter:title":
+			card.Title = value
+		case "twitter:description":
+			card.Description = value
+		case "twitter:image", "twitter:image:src":
+			if card.Image == "" {
+				card.Image = resolveURL(base, value)
+			}
+		case "twitter:image:alt":
+			card.ImageAlt = value
+		}
+	}
+	return card
+}
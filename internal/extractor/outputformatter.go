@@ -1,25 +1,26 @@
 package extractor
 
 import (
-	"bytes"
+	"context"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/advancedlogic/GoOse/pkg/goose"
+	"github.com/advancedlogic/GoOse/internal/types"
 	"golang.org/x/net/html"
 )
 
-var normalizeWhitespaceRegexp = regexp.MustCompile(`[ \r\f\v\t]+`)
-var normalizeNl = regexp.MustCompile(`\n{3,}`)
-var multipleSpaces = regexp.MustCompile(`\n\s*\n\s*\n`)
 var validURLRegex = regexp.MustCompile("^http[s]?://")
 
 type outputFormatter struct {
 	topNode  *goquery.Selection
-	config   goose.Configuration
+	config   types.Configuration
 	language string
+	// structured tells whether a child of the top node is kept whatever its number of words,
+	// e.g. a heading
+	structured func(*html.Node) bool
 }
 
 func (formatter *outputFormatter) getLanguage(lang string) string {
@@ -33,19 +34,29 @@ func (formatter *outputFormatter) getTopNode() *goquery.Selection {
 	return formatter.topNode
 }
 
-func (formatter *outputFormatter) getFormattedText(topNode *goquery.Selection, lang string) (output string, links []string) {
+// getFormattedBlocks removes the nodes with a negative score and the short paragraphs of the
+// top node, and splits it into blocks. The emphasis are the text nodes of the <em> elements
+// the cleaner unwrapped
+func (formatter *outputFormatter) getFormattedBlocks(ctx context.Context, topNode *goquery.Selection, lang string, pageURL string, emphasis map[*html.Node]bool) (blocks []types.Block, links []string, err error) {
 	formatter.topNode = topNode
 	formatter.language = formatter.getLanguage(lang)
 	if formatter.language == "" {
 		formatter.language = formatter.config.TargetLanguage
 	}
-	formatter.removeNegativescoresNodes()
-	links = formatter.linksToText()
-	formatter.replaceTagsWithText()
-	formatter.removeParagraphsWithFewWords()
-
-	output = formatter.getOutputText()
-	return output, links
+	base, _ := url.Parse(pageURL)
+	steps := []func(){
+		formatter.removeNegativescoresNodes,
+		func() { links = formatter.linksURLs() },
+		formatter.removeParagraphsWithFewWords,
+		func() { blocks = getBlocks(formatter.topNode.Nodes, base, emphasis) },
+	}
+	for _, step := range steps {
+		if err = ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		step()
+	}
+	return blocks, links, nil
 }
 
 func (formatter *outputFormatter) convertToText() string {
@@ -67,7 +78,8 @@ func isValidURL(u string) bool {
 	return validURLRegex.MatchString(u)
 }
 
-func (formatter *outputFormatter) linksToText() []string {
+// linksURLs returns the absolute URLs of the links of the top node, the linked images aside
+func (formatter *outputFormatter) linksURLs() []string {
 	var urlList []string
 	links := formatter.topNode.Find("a")
 	links.Each(func(i int, a *goquery.Selection) {
@@ -79,97 +91,12 @@ func (formatter *outputFormatter) linksToText() []string {
 			if isValidURL(url) {
 				urlList = append(urlList, url)
 			}
-			// replace <a> tag with its text contents
-			replaceTagWithContents(a, whitelistedExtAtomTypes)
-
-			// see whether we can collapse the parent node now
-			replaceTagWithContents(a.Parent(), whitelistedTextAtomTypes)
 		}
 	})
 
 	return urlList
 }
 
-// Text gets the combined text contents of each element in the set of matched
-// elements, including their descendants.
-//
-// @see https://github.com/PuerkitoBio/goquery/blob/master/property.go
-func (formatter *outputFormatter) Text(s *goquery.Selection) string {
-	var buf bytes.Buffer
-
-	// Slightly optimized vs calling Each: no single selection object created
-	var f func(*html.Node)
-	f = func(n *html.Node) {
-		if n.Type == html.TextNode && 0 == n.DataAtom { // NB: had to add the DataAtom check to avoid printing text twice when a textual node embeds another textual node
-			// Keep newlines and spaces, like jQuery
-			buf.WriteString(n.Data)
-		}
-		if n.FirstChild != nil {
-			for c := n.FirstChild; c != nil; c = c.NextSibling {
-				f(c)
-			}
-		}
-	}
-	for _, n := range s.Nodes {
-		f(n)
-	}
-
-	return buf.String()
-}
-
-func (formatter *outputFormatter) getOutputText() string {
-	//out := formatter.topNode.Text()
-	out := formatter.Text(formatter.topNode)
-	out = normalizeWhitespaceRegexp.ReplaceAllString(out, " ")
-
-	strArr := strings.Split(out, "\n")
-	resArr := []string{}
-	lastWasEmpty := false
-
-	for _, v := range strArr {
-		v = strings.TrimSpace(v)
-		if v != "" {
-			resArr = append(resArr, v)
-			lastWasEmpty = false
-		} else if !lastWasEmpty && len(resArr) > 0 {
-			// Only add one empty line between paragraphs
-			resArr = append(resArr, "")
-			lastWasEmpty = true
-		}
-	}
-
-	out = strings.Join(resArr, "\n")
-	// More aggressive whitespace cleanup
-	out = normalizeNl.ReplaceAllString(out, "\n\n")
-	out = multipleSpaces.ReplaceAllString(out, "\n\n")
-	
-	// Final cleanup: remove leading/trailing whitespace
-	out = strings.TrimSpace(out)
-	
-	// Additional cleanup for cases where content extraction has issues
-	lines := strings.Split(out, "\n")
-	cleanedLines := []string{}
-	seenContent := make(map[string]bool)
-	
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		
-		// Skip lines that are clearly navigation or metadata
-		if line != "" && !seenContent[line] && !formatter.isNavigationLine(line) {
-			cleanedLines = append(cleanedLines, line)
-			seenContent[line] = true
-		} else if line == "" && len(cleanedLines) > 0 && cleanedLines[len(cleanedLines)-1] != "" {
-			// Only add empty lines between different content blocks
-			cleanedLines = append(cleanedLines, "")
-		}
-	}
-	
-	out = strings.Join(cleanedLines, "\n")
-	out = strings.TrimSpace(out)
-	
-	return out
-}
-
 func (formatter *outputFormatter) removeNegativescoresNodes() {
 	gravityItems := formatter.topNode.Find("*[gravityScore]")
 	gravityItems.Each(func(i int, s *goquery.Selection) {
@@ -186,15 +113,6 @@ func (formatter *outputFormatter) removeNegativescoresNodes() {
 	})
 }
 
-func (formatter *outputFormatter) replaceTagsWithText() {
-	for _, tag := range []string{"em", "strong", "b", "i", "span", "h1", "h2", "h3", "h4"} {
-		nodes := formatter.topNode.Find(tag)
-		nodes.Each(func(i int, node *goquery.Selection) {
-			replaceTagWithContents(node, whitelistedTextAtomTypes)
-		})
-	}
-}
-
 func (formatter *outputFormatter) removeParagraphsWithFewWords() {
 	language := formatter.language
 	if language == "" {
@@ -204,7 +122,12 @@ func (formatter *outputFormatter) removeParagraphsWithFewWords() {
 	allNodes.Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		wordCount := len(strings.Fields(text))
-		if wordCount < 5 && s.Find("object").Length() == 0 && s.Find("em").Length() == 0 {
+		// the links are read as text, and so are the paragraphs made of them
+		links := s.ChildrenFiltered("a").FilterFunction(func(i int, a *goquery.Selection) bool {
+			return a.Find("img").Length() == 0
+		})
+		if wordCount < 5 && s.Find("object").Length() == 0 && s.Find("em").Length() == 0 && links.Length() == 0 &&
+			(formatter.structured == nil || !formatter.structured(s.Get(0))) {
 			node := s.Get(0)
 			if node.Parent != nil {
 				node.Parent.RemoveChild(node)
@@ -214,7 +137,7 @@ func (formatter *outputFormatter) removeParagraphsWithFewWords() {
 }
 
 // isNavigationLine checks if a line of text is likely navigation or metadata
-func (formatter *outputFormatter) isNavigationLine(line string) bool {
+func isNavigationLine(line string) bool {
 	if len(line) == 0 {
 		return false
 	}
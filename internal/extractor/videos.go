@@ -5,14 +5,14 @@ import (
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/advancedlogic/GoOse/internal/types"
 	"github.com/fatih/set"
-	"github.com/advancedlogic/GoOse/pkg/goose"
 )
 
 // VideoExtractor can extract the main video from an HTML page
 type VideoExtractor struct {
-	article    *goose.Article
-	config     goose.Configuration
+	article    *types.Article
+	config     types.Configuration
 	candidates *set.Set
 	movies     *set.Set
 }
@@ -0,0 +1,231 @@
+package fetcher
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache statuses reported by FetchResult.CacheStatus
+const (
+	CacheMiss        = "miss"        // fetched from the server
+	CacheHit         = "hit"         // served from the cache without contacting the server
+	CacheRevalidated = "revalidated" // the server answered 304 Not Modified
+)
+
+// CachedResponse is a response kept by a Cache, with its body already decoded
+type CachedResponse struct {
+	StatusCode int         `json:"statuscode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	FinalURL   string      `json:"finalurl"`
+	Redirects  []string    `json:"redirects,omitempty"`
+	StoredAt   time.Time   `json:"storedat"`
+}
+
+// Cache stores the fetched responses, keyed by requested URL.
+// Implementations must be safe for concurrent use
+type Cache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, response *CachedResponse)
+}
+
+// fresh tells whether the response can still be served without asking the server
+func (r *CachedResponse) fresh(now time.Time) bool {
+	directives := cacheControl(r.Header)
+	if _, noCache := directives["no-cache"]; noCache {
+		return false
+	}
+	if maxAge, ok := directives["max-age"]; ok {
+		seconds, err := strconv.Atoi(maxAge)
+		return err == nil && now.Before(r.StoredAt.Add(time.Duration(seconds)*time.Second))
+	}
+	if expires, err := http.ParseTime(r.Header.Get("Expires")); err == nil {
+		return now.Before(expires)
+	}
+	return false
+}
+
+// result returns a copy of the response as a FetchResult
+func (r *CachedResponse) result(cacheStatus string) *FetchResult {
+	return &FetchResult{
+		Body:        r.Body,
+		StatusCode:  r.StatusCode,
+		Header:      r.Header.Clone(),
+		FinalURL:    r.FinalURL,
+		Redirects:   r.Redirects,
+		CacheStatus: cacheStatus,
+	}
+}
+
+// conditionalHeaders returns the headers revalidating the response with the server
+func (r *CachedResponse) conditionalHeaders() http.Header {
+	header := http.Header{}
+	if etag := r.Header.Get("ETag"); etag != "" {
+		header.Set("If-None-Match", etag)
+	}
+	if lastModified := r.Header.Get("Last-Modified"); lastModified != "" {
+		header.Set("If-Modified-Since", lastModified)
+	}
+	return header
+}
+
+// cacheable tells whether a response with this header may be stored
+func cacheable(header http.Header) bool {
+	_, noStore := cacheControl(header)["no-store"]
+	return !noStore
+}
+
+// cacheControl parses the Cache-Control directives, lowercasing their names
+func cacheControl(header http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name != "" {
+				directives[strings.ToLower(name)] = strings.Trim(arg, `"`)
+			}
+		}
+	}
+	return directives
+}
+
+// MemoryCache is a Cache keeping the most recently used responses in memory
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // most recently used first
+}
+
+type memoryEntry struct {
+	key      string
+	response *CachedResponse
+}
+
+// NewMemoryCache returns an LRU cache holding up to capacity responses
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the response stored for the key
+func (c *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*memoryEntry).response, true
+}
+
+// Set stores the response, evicting the least recently used one when the cache is full
+func (c *MemoryCache) Set(key string, response *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*memoryEntry).response = response
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, response: response})
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// DiskCache is a Cache storing each response as a JSON file in a directory.
+// Read and write failures are treated as cache misses
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a cache storing the responses in dir, created when needed
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{dir: dir}
+}
+
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the response stored for the key
+func (c *DiskCache) Get(key string) (*CachedResponse, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	response := new(CachedResponse)
+	if err := json.Unmarshal(data, response); err != nil {
+		return nil, false
+	}
+	return response, true
+}
+
+// Set stores the response, replacing the file atomically
+func (c *DiskCache) Set(key string, response *CachedResponse) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(c.dir, "*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	os.Rename(tmp.Name(), c.path(key))
+}
+
+// layeredCache looks the responses up in each cache in turn, the fastest first
+type layeredCache []Cache
+
+// NewLayeredCache returns a Cache made of the given caches, the fastest first, e.g. a
+// MemoryCache in front of a DiskCache. Responses found in a slower layer are copied
+// to the faster ones
+func NewLayeredCache(caches ...Cache) Cache {
+	return layeredCache(caches)
+}
+
+func (l layeredCache) Get(key string) (*CachedResponse, bool) {
+	for i, cache := range l {
+		if response, ok := cache.Get(key); ok {
+			for _, faster := range l[:i] {
+				faster.Set(key, response)
+			}
+			return response, true
+		}
+	}
+	return nil, false
+}
+
+func (l layeredCache) Set(key string, response *CachedResponse) {
+	for _, cache := range l {
+		cache.Set(key, response)
+	}
+}
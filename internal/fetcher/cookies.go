@@ -0,0 +1,180 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// PersistentCookieJar is an http.CookieJar saving its cookies to a JSON file, so that
+// they outlive the process (e.g. the consent given to a publisher). It is safe for
+// concurrent use, and can be shared by several Goose instances
+type PersistentCookieJar struct {
+	path string
+	jar  *cookiejar.Jar
+
+	mu      sync.Mutex
+	cookies map[string]persistedCookie
+}
+
+// persistedCookie is a cookie along the URL that set it
+type persistedCookie struct {
+	URL    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+// NewPersistentCookieJar returns a jar loading its cookies from the file at path, when it
+// exists, and saving them there each time a response sets some
+func NewPersistentCookieJar(path string) (*PersistentCookieJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	j := &PersistentCookieJar{
+		path:    path,
+		jar:     jar,
+		cookies: make(map[string]persistedCookie),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the cookie jar")
+	}
+	var saved []persistedCookie
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, errors.Wrap(err, "could not parse the cookie jar "+path)
+	}
+	now := time.Now()
+	for _, entry := range saved {
+		u, err := url.Parse(entry.URL)
+		if err != nil || entry.Cookie == nil || (!entry.Cookie.Expires.IsZero() && entry.Cookie.Expires.Before(now)) {
+			continue
+		}
+		j.jar.SetCookies(u, []*http.Cookie{entry.Cookie})
+		j.cookies[cookieKey(u, entry.Cookie)] = entry
+	}
+	return j, nil
+}
+
+// Cookies returns the cookies to send in a request for the URL
+func (j *PersistentCookieJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
+
+// SetCookies stores the cookies received in a response for the URL and saves the jar.
+// Saving failures are ignored, Save reports them
+func (j *PersistentCookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.jar.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	now := time.Now()
+	for _, cookie := range cookies {
+		key := cookieKey(u, cookie)
+		if cookie.MaxAge < 0 || (!cookie.Expires.IsZero() && cookie.Expires.Before(now)) {
+			delete(j.cookies, key)
+			continue
+		}
+		stored := *cookie
+		if stored.MaxAge > 0 {
+			// Max-Age is relative to the time the cookie is received
+			stored.Expires = now.Add(time.Duration(stored.MaxAge) * time.Second)
+			stored.MaxAge = 0
+		}
+		stored.Raw = ""
+		j.cookies[key] = persistedCookie{URL: (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String(), Cookie: &stored}
+	}
+	j.mu.Unlock()
+	j.Save()
+}
+
+// Save writes the cookies to the file, replacing it atomically
+func (j *PersistentCookieJar) Save() error {
+	j.mu.Lock()
+	saved := make([]persistedCookie, 0, len(j.cookies))
+	for _, entry := range j.cookies {
+		saved = append(saved, entry)
+	}
+	data, err := json.Marshal(saved)
+	j.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(j.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.Wrap(err, "could not save the cookie jar")
+	}
+	tmp, err := os.CreateTemp(dir, "*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "could not save the cookie jar")
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "could not save the cookie jar")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "could not save the cookie jar")
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), j.path), "could not save the cookie jar")
+}
+
+func cookieKey(u *url.URL, cookie *http.Cookie) string {
+	domain := strings.ToLower(strings.TrimPrefix(cookie.Domain, "."))
+	if domain == "" {
+		domain = u.Hostname()
+	}
+	return domain + ";" + cookie.Path + ";" + cookie.Name
+}
+
+// matchesDomain tells whether the host is the domain or one of its subdomains
+func matchesDomain(host string, domain string) bool {
+	host, domain = strings.ToLower(host), strings.ToLower(strings.TrimPrefix(domain, "."))
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// addPresetCookies adds to the request the preset cookies of its domain that the
+// client jar does not hold yet. With a jar, they are seeded into it so that they
+// follow the redirects within the domain
+func (f *HTTPFetcher) addPresetCookies(req *http.Request) {
+	host := req.URL.Hostname()
+	for domain, cookies := range f.PresetCookies {
+		if !matchesDomain(host, domain) {
+			continue
+		}
+		present := make(map[string]bool)
+		if f.client.Jar != nil {
+			for _, cookie := range f.client.Jar.Cookies(req.URL) {
+				present[cookie.Name] = true
+			}
+		}
+		for _, cookie := range cookies {
+			if present[cookie.Name] {
+				continue
+			}
+			if f.client.Jar == nil {
+				req.AddCookie(cookie)
+				continue
+			}
+			seeded := *cookie
+			if seeded.Domain == "" {
+				seeded.Domain = strings.TrimPrefix(domain, ".")
+			}
+			if seeded.Path == "" {
+				seeded.Path = "/"
+			}
+			f.client.Jar.SetCookies(req.URL, []*http.Cookie{&seeded})
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package fetcher
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/pkg/errors"
+)
+
+// ErrBodyTooLarge is returned when a response exceeds the maximum body or decompressed size
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// FetchError is returned when a page cannot be fetched, either because of a network
+// failure or because the server answered with an error status code
+type FetchError struct {
+	URL        string
+	StatusCode int // 0 for network failures
+	Err        error
+}
+
+func (e *FetchError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("could not perform request on %s: status code %d", e.URL, e.StatusCode)
+	}
+	return fmt.Sprintf("could not perform request on %s: %v", e.URL, e.Err)
+}
+
+// Unwrap returns the underlying network error, if any
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// UnsupportedContentTypeError is returned for responses whose content type cannot be extracted
+type UnsupportedContentTypeError struct {
+	URL         string
+	ContentType string
+}
+
+func (e *UnsupportedContentTypeError) Error() string {
+	return fmt.Sprintf("unsupported content type %q for %s", e.ContentType, e.URL)
+}
+
+// HTMLContentTypes are the media types accepted by default
+var HTMLContentTypes = []string{"text/html", "application/xhtml+xml"}
+
+// MediaType returns the lowercase media type of a Content-Type header, without its parameters
+func MediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.TrimSpace(strings.Split(contentType, ";")[0])
+	}
+	return strings.ToLower(mediaType)
+}
+
+// IsHTML tells whether the Content-Type header announces an HTML document.
+// A missing header is given the benefit of the doubt
+func IsHTML(contentType string) bool {
+	return contentType == "" || acceptsContentType(HTMLContentTypes, contentType)
+}
+
+func acceptsContentType(mediaTypes []string, contentType string) bool {
+	mediaType := MediaType(contentType)
+	for _, accepted := range mediaTypes {
+		if mediaType == accepted {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetcher retrieves the raw content of a web page
+type Fetcher interface {
+	Fetch(ctx context.Context, targetURL string) (*FetchResult, error)
+}
+
+// FetchResult is the response returned by a Fetcher
+type FetchResult struct {
+	Body       []byte
+	StatusCode int
+	Header     http.Header
+	// FinalURL is the URL the content was eventually served from
+	FinalURL string
+	// Redirects lists the URLs that answered with a redirect, in the order they were visited
+	Redirects []string
+	// Retries is the number of attempts that failed before this response
+	Retries int
+	// CacheStatus tells whether the response is a cache hit, a revalidated (304) or
+	// a fresh response; it is empty when no cache is used
+	CacheStatus string
+	// QueueWait is the time spent waiting for a HostLimiter
+	QueueWait time.Duration
+}
+
+// HTTPFetcher is the default Fetcher, backed by a net/http client
+type HTTPFetcher struct {
+	client    *http.Client
+	userAgent string
+
+	// MaxBodySize caps the number of bytes read from the wire (0 means no limit)
+	MaxBodySize int64
+	// MaxDecompressedSize caps the size of gzip, deflate or brotli bodies once decoded (0 means no limit)
+	MaxDecompressedSize int64
+	// ContentTypes lists the accepted media types; the body of any other type is not read
+	// and an UnsupportedContentTypeError is returned. Responses without a Content-Type are
+	// always accepted. When empty, every type is accepted
+	ContentTypes []string
+	// RetryPolicy tells which failed requests are tried again (none with the zero value)
+	RetryPolicy RetryPolicy
+	// Cache, when set, stores the responses and revalidates them with
+	// If-None-Match and If-Modified-Since once they are stale
+	Cache Cache
+	// PresetCookies are sent to the hosts of each domain (and its subdomains) unless
+	// the client jar already holds cookies with the same names, e.g. to give consent
+	PresetCookies map[string][]*http.Cookie
+}
+
+// NewHTTPFetcher returns a Fetcher using the given client (a zero http.Client when nil).
+// Proxies, TLS settings or instrumentation can be injected through the client Transport.
+func NewHTTPFetcher(client *http.Client, userAgent string) *HTTPFetcher {
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &HTTPFetcher{
+		client:    client,
+		userAgent: userAgent,
+	}
+}
+
+// Fetch performs a GET request on the target URL and reads the whole response body
+func (f *HTTPFetcher) Fetch(ctx context.Context, targetURL string) (*FetchResult, error) {
+	if _, err := url.Parse(targetURL); err != nil {
+		return nil, errors.Wrap(err, "could not parse "+targetURL)
+	}
+
+	var (
+		cached      *CachedResponse
+		conditional http.Header
+	)
+	if f.Cache != nil {
+		var ok bool
+		if cached, ok = f.Cache.Get(targetURL); ok {
+			if cached.fresh(time.Now()) {
+				return cached.result(CacheHit), nil
+			}
+			conditional = cached.conditionalHeaders()
+		}
+	}
+
+	var (
+		resp    *http.Response
+		err     error
+		retries int
+	)
+	for attempt := 1; ; attempt++ {
+		resp, err = f.do(ctx, targetURL, conditional)
+		delay, retry := f.RetryPolicy.nextDelay(attempt, resp, err)
+		if !retry {
+			break
+		}
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096)) // lets the connection be reused
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		retries++
+	}
+	if err != nil {
+		return nil, &FetchError{URL: targetURL, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		// the headers sent along the 304 supersede the stored ones
+		revalidated := *cached
+		revalidated.Header = cached.Header.Clone()
+		for name, values := range resp.Header {
+			if name != "Content-Length" && name != "Content-Encoding" {
+				revalidated.Header[name] = values
+			}
+		}
+		revalidated.StoredAt = time.Now()
+		f.Cache.Set(targetURL, &revalidated)
+		result := revalidated.result(CacheRevalidated)
+		result.Retries = retries
+		return result, nil
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, &FetchError{URL: targetURL, StatusCode: resp.StatusCode}
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && len(f.ContentTypes) > 0 && !acceptsContentType(f.ContentTypes, contentType) {
+		return nil, &UnsupportedContentTypeError{URL: targetURL, ContentType: contentType}
+	}
+	if f.MaxBodySize > 0 && resp.ContentLength > f.MaxBodySize {
+		return nil, errors.Wrapf(ErrBodyTooLarge, "%s announces %d bytes", targetURL, resp.ContentLength)
+	}
+
+	body, err := f.readBody(resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read response from "+targetURL)
+	}
+
+	result := &FetchResult{
+		Body:       body,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		FinalURL:   resp.Request.URL.String(),
+		Redirects:  redirectChain(resp),
+		Retries:    retries,
+	}
+	if f.Cache != nil {
+		result.CacheStatus = CacheMiss
+		if resp.StatusCode == http.StatusOK && cacheable(resp.Header) {
+			f.Cache.Set(targetURL, &CachedResponse{
+				StatusCode: result.StatusCode,
+				Header:     result.Header.Clone(),
+				Body:       result.Body,
+				FinalURL:   result.FinalURL,
+				Redirects:  result.Redirects,
+				StoredAt:   time.Now(),
+			})
+		}
+	}
+	return result, nil
+}
+
+// do sends a single GET request, with the additional headers if any
+func (f *HTTPFetcher) do(ctx context.Context, targetURL string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create request for "+targetURL)
+	}
+	if f.userAgent != "" {
+		req.Header.Set("User-Agent", f.userAgent)
+	}
+	// asking for the encodings ourselves disables the transparent gzip support of net/http
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	f.addPresetCookies(req)
+	return f.client.Do(req)
+}
+
+// readBody reads the response body within the size limits, decoding it according to its Content-Encoding
+func (f *HTTPFetcher) readBody(resp *http.Response) ([]byte, error) {
+	raw := limitReader(resp.Body, f.MaxBodySize)
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	var (
+		decoder io.Reader
+		err     error
+	)
+	switch encoding {
+	case "", "identity":
+		return io.ReadAll(raw)
+	case "gzip", "x-gzip":
+		if decoder, err = gzip.NewReader(raw); err != nil {
+			return nil, errors.Wrap(err, "invalid gzip body")
+		}
+	case "deflate":
+		// deflate is supposed to be zlib wrapped, but some servers send raw deflate data
+		buffered := bufio.NewReader(raw)
+		if header, err := buffered.Peek(2); err == nil && (uint(header[0])<<8|uint(header[1]))%31 == 0 && header[0]&0x0f == 8 {
+			if decoder, err = zlib.NewReader(buffered); err != nil {
+				return nil, errors.Wrap(err, "invalid deflate body")
+			}
+		} else {
+			decoder = flate.NewReader(buffered)
+		}
+	case "br":
+		decoder = brotli.NewReader(raw)
+	default:
+		return nil, errors.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	body, err := io.ReadAll(limitReader(decoder, f.MaxDecompressedSize))
+	if err != nil {
+		return nil, err
+	}
+	// the header now describes the decoded body
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	return body, nil
+}
+
+// limitReader returns a reader failing with ErrBodyTooLarge once more than limit bytes were read
+func limitReader(r io.Reader, limit int64) io.Reader {
+	if limit <= 0 {
+		return r
+	}
+	return &limitedReader{r: r, remaining: limit}
+}
+
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrBodyTooLarge
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, ErrBodyTooLarge
+	}
+	return n, err
+}
+
+// redirectChain walks back the responses that led to resp and returns their URLs, oldest first
+func redirectChain(resp *http.Response) []string {
+	var chain []string
+	for r := resp.Request.Response; r != nil; r = r.Request.Response {
+		chain = append([]string{r.Request.URL.String()}, chain...)
+	}
+	return chain
+}
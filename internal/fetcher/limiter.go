@@ -0,0 +1,201 @@
+package fetcher
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LimiterStats are the metrics of a HostLimiter, for all the hosts or a single one
+type LimiterStats struct {
+	// Requests is the number of requests let through
+	Requests int64
+	// Waiting is the number of requests currently queued
+	Waiting int
+	// TotalWait and MaxWait are the cumulated and the longest time spent in the queue
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+func (s *LimiterStats) record(wait time.Duration) {
+	s.Requests++
+	s.TotalWait += wait
+	if wait > s.MaxWait {
+		s.MaxWait = wait
+	}
+}
+
+// HostLimiter throttles the requests sent to each host: a token bucket bounds their
+// rate and a semaphore the number of them running at once. It is safe for concurrent
+// use, and can be shared by several Goose instances
+type HostLimiter struct {
+	rate          float64 // requests per second and per host, 0 for no limit
+	burst         int
+	maxConcurrent int // per host, 0 for no limit
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+	stats LimiterStats
+}
+
+type hostState struct {
+	tokens float64
+	last   time.Time
+	slots  chan struct{} // nil when the concurrency is not capped
+	stats  LimiterStats
+}
+
+// NewHostLimiter returns a limiter letting through, for each host, requestsPerSecond
+// requests per second with bursts of up to burst requests, and at most maxConcurrent
+// requests at once. A zero value disables the corresponding limit
+func NewHostLimiter(requestsPerSecond float64, burst int, maxConcurrent int) *HostLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &HostLimiter{
+		rate:          requestsPerSecond,
+		burst:         burst,
+		maxConcurrent: maxConcurrent,
+		hosts:         make(map[string]*hostState),
+	}
+}
+
+// Wait blocks until a request can be sent to the host, spacing the requests by at
+// least crawlDelay (e.g. the Crawl-delay of robots.txt) when it is not 0.
+// It returns the time spent waiting, and a function to call once the request is done
+func (l *HostLimiter) Wait(ctx context.Context, host string, crawlDelay time.Duration) (func(), time.Duration, error) {
+	start := time.Now()
+	l.mu.Lock()
+	state, ok := l.hosts[host]
+	if !ok {
+		state = &hostState{tokens: float64(l.burst), last: start}
+		if l.maxConcurrent > 0 {
+			state.slots = make(chan struct{}, l.maxConcurrent)
+		}
+		l.hosts[host] = state
+	}
+	state.stats.Waiting++
+	l.stats.Waiting++
+	l.mu.Unlock()
+
+	release, err := l.acquire(ctx, state, crawlDelay)
+	wait := time.Since(start)
+
+	l.mu.Lock()
+	state.stats.Waiting--
+	l.stats.Waiting--
+	if err == nil {
+		state.stats.record(wait)
+		l.stats.record(wait)
+	}
+	l.mu.Unlock()
+	return release, wait, err
+}
+
+// acquire takes a concurrency slot, then waits for a token
+func (l *HostLimiter) acquire(ctx context.Context, state *hostState, crawlDelay time.Duration) (func(), error) {
+	release := func() {}
+	if state.slots != nil {
+		select {
+		case state.slots <- struct{}{}:
+			release = func() { <-state.slots }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	rate, burst := l.rate, float64(l.burst)
+	if crawlDelay > 0 {
+		if delayRate := 1 / crawlDelay.Seconds(); rate == 0 || delayRate < rate {
+			rate = delayRate
+		}
+		burst = 1
+	}
+	if rate == 0 {
+		return release, nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	state.tokens = math.Min(burst, state.tokens+now.Sub(state.last).Seconds()*rate)
+	state.last = now
+	// the token is reserved even if it is not available yet, so that the waiting requests queue up
+	state.tokens--
+	delay := time.Duration(-state.tokens / rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay > 0 {
+		if err := sleep(ctx, delay); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// Stats returns the metrics of all the hosts
+func (l *HostLimiter) Stats() LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// HostStats returns the metrics of a host
+func (l *HostLimiter) HostStats(host string) LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if state, ok := l.hosts[host]; ok {
+		return state.stats
+	}
+	return LimiterStats{}
+}
+
+// LimitedFetcher is a Fetcher waiting for a HostLimiter before delegating to another Fetcher
+type LimitedFetcher struct {
+	next      Fetcher
+	limiter   *HostLimiter
+	robots    *RobotsChecker
+	userAgent string
+}
+
+// NewLimitedFetcher returns a Fetcher throttled by the limiter. When robots is not nil,
+// the Crawl-delay it finds for the user agent spaces the requests to each host
+func NewLimitedFetcher(next Fetcher, limiter *HostLimiter, robots *RobotsChecker, userAgent string) *LimitedFetcher {
+	return &LimitedFetcher{
+		next:      next,
+		limiter:   limiter,
+		robots:    robots,
+		userAgent: userAgent,
+	}
+}
+
+// Fetch waits for the limiter and fetches the URL, reporting the time spent waiting in QueueWait
+func (f *LimitedFetcher) Fetch(ctx context.Context, targetURL string) (*FetchResult, error) {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse "+targetURL)
+	}
+	var crawlDelay time.Duration
+	if f.robots != nil {
+		if crawlDelay, err = f.robots.CrawlDelay(ctx, targetURL, f.userAgent); err != nil {
+			return nil, err
+		}
+	}
+
+	release, wait, err := f.limiter.Wait(ctx, u.Host, crawlDelay)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	result, err := f.next.Fetch(ctx, targetURL)
+	if err != nil {
+		return nil, err
+	}
+	result.QueueWait += wait
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// FetchFollowingRefresh fetches the target URL and follows up to maxHops
+// <meta http-equiv="refresh"> redirects. The Redirects of the returned result
+// contain both the HTTP redirects and the meta refresh hops, and its Retries
+// and QueueWait add up those of every hop.
+func FetchFollowingRefresh(ctx context.Context, f Fetcher, targetURL string, maxHops int) (*FetchResult, error) {
+	var redirects []string
+	retries, queueWait := 0, time.Duration(0)
+	for hop := 0; ; hop++ {
+		result, err := f.Fetch(ctx, targetURL)
+		if err != nil {
+			return nil, err
+		}
+		if result.FinalURL == "" {
+			result.FinalURL = targetURL
+		}
+		redirects = append(redirects, result.Redirects...)
+		result.Redirects = redirects
+		retries += result.Retries
+		result.Retries = retries
+		queueWait += result.QueueWait
+		result.QueueWait = queueWait
+
+		if hop >= maxHops || !IsHTML(result.Header.Get("Content-Type")) {
+			return result, nil
+		}
+		next := MetaRefreshURL(result.Body, result.FinalURL)
+		if next == "" || next == result.FinalURL {
+			return result, nil
+		}
+		redirects = append(redirects, result.FinalURL)
+		targetURL = next
+	}
+}
+
+// MetaRefreshURL returns the absolute target of the first <meta http-equiv="refresh">
+// tag in the page, or an empty string if there is none
+func MetaRefreshURL(body []byte, baseURL string) string {
+	document, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	content, _ := document.Find("meta[http-equiv#=(?i)^refresh$]").First().Attr("content")
+	target := parseRefreshContent(content)
+	if target == "" {
+		return ""
+	}
+
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	ref, err := url.Parse(target)
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// parseRefreshContent extracts the URL from a refresh directive such as "0; url='/next'"
+func parseRefreshContent(content string) string {
+	idx := strings.IndexAny(content, ";,")
+	if idx == -1 {
+		// only a delay, the page refreshes itself
+		return ""
+	}
+	target := strings.TrimSpace(content[idx+1:])
+	if len(target) >= 4 && strings.EqualFold(target[:3], "url") {
+		if rest := strings.TrimSpace(target[3:]); strings.HasPrefix(rest, "=") {
+			target = strings.TrimSpace(rest[1:])
+		}
+	}
+	target = strings.Trim(target, `'"`)
+	return strings.TrimSpace(target)
+}
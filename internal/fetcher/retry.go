@@ -0,0 +1,155 @@
+package fetcher
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy tells the HTTPFetcher when and how long to wait before trying a request again
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, the first one included (0 or 1 disables the retries)
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled at each following attempt up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Jitter randomly shortens each delay by up to this fraction (between 0 and 1)
+	Jitter float64
+	// RetryableStatusCodes lists the status codes worth another attempt
+	RetryableStatusCodes []int
+	// RetryableError tells whether a network error is worth another attempt.
+	// When nil, IsTransientError is used
+	RetryableError func(error) bool
+	// MaxRetryAfter is the longest Retry-After delay (sent along 429 and 503 responses)
+	// the fetcher agrees to wait; the request fails when the server asks for more.
+	// When 0, MaxDelay is used
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy returns a policy making 3 attempts for the usual transient failures
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.5,
+		RetryableStatusCodes: []int{
+			http.StatusRequestTimeout,
+			http.StatusTooEarly,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// IsTransientError tells whether a network error is likely to go away on its own:
+// timeouts, refused or reset connections and connections closed too early
+func IsTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// nextDelay returns the delay before the next attempt, and false when the request
+// should not be tried again after the given attempt (counted from 1)
+func (p RetryPolicy) nextDelay(attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if err != nil {
+		retryable := p.RetryableError
+		if retryable == nil {
+			retryable = IsTransientError
+		}
+		return p.backoff(attempt), retryable(err)
+	}
+	if !p.retryableStatus(resp.StatusCode) {
+		return 0, false
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if delay, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			maxRetryAfter := p.MaxRetryAfter
+			if maxRetryAfter == 0 {
+				maxRetryAfter = p.MaxDelay
+			}
+			return delay, maxRetryAfter <= 0 || delay <= maxRetryAfter
+		}
+	}
+	return p.backoff(attempt), true
+}
+
+func (p RetryPolicy) retryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the exponential delay before the retry following the given attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		delay -= time.Duration(p.Jitter * rand.Float64() * float64(delay))
+	}
+	return delay
+}
+
+// retryAfter parses a Retry-After header, either a number of seconds or an HTTP date
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	delay := time.Until(date)
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, true
+}
+
+// sleep waits for the delay unless the context is done first
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
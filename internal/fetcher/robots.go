@@ -0,0 +1,369 @@
+package fetcher
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RobotsDisallowedError is returned for the URLs that robots.txt forbids to the user agent
+type RobotsDisallowedError struct {
+	URL       string
+	UserAgent string
+}
+
+func (e *RobotsDisallowedError) Error() string {
+	return fmt.Sprintf("%s is disallowed by robots.txt for %q", e.URL, e.UserAgent)
+}
+
+const (
+	// robotsTTL is how long a robots.txt file is kept before being fetched again
+	robotsTTL = 24 * time.Hour
+	// robotsFailureTTL is how long a host whose robots.txt could not be fetched is considered fully disallowed
+	robotsFailureTTL = time.Minute
+	// robotsMaxSize is the size of the robots.txt files read by the default fetcher, the minimum
+	// RFC 9309 asks the crawlers to parse
+	robotsMaxSize = 500 << 10
+)
+
+// RobotsChecker fetches and caches the robots.txt file of each host, and tells whether
+// a user agent may fetch a URL. It is safe for concurrent use, and can be shared by
+// several Goose instances
+type RobotsChecker struct {
+	fetcher Fetcher
+	client  *http.Client // of the default fetcher, when fetcher is nil
+
+	mu    sync.Mutex
+	hosts map[string]*robotsHost
+}
+
+type robotsHost struct {
+	ready   chan struct{} // closed once the robots.txt file is fetched
+	rules   *robotsRules
+	expires time.Time
+	// lastAccess is the time of the last request allowed for each user agent, to honour Crawl-delay
+	lastAccess map[string]time.Time
+}
+
+// NewRobotsChecker returns a checker fetching the robots.txt files with f. When f is nil,
+// they are fetched with the user agent of the first check on each host, and those over
+// 500 KiB are considered unreachable
+func NewRobotsChecker(f Fetcher) *RobotsChecker {
+	c := &RobotsChecker{
+		fetcher: f,
+		hosts:   make(map[string]*robotsHost),
+	}
+	if f == nil {
+		c.client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return c
+}
+
+// Check returns a RobotsDisallowedError if robots.txt forbids the URL to the user agent.
+// Otherwise it waits, if needed, for the Crawl-delay since the previous allowed request
+// on the same host, and returns nil
+func (c *RobotsChecker) Check(ctx context.Context, targetURL string, userAgent string) error {
+	host, group, err := c.check(ctx, targetURL, userAgent)
+	if err != nil || host == nil || group.crawlDelay <= 0 {
+		return err
+	}
+
+	// reserve the next slot before waiting, so that concurrent requests queue up
+	c.mu.Lock()
+	next := host.lastAccess[userAgent].Add(group.crawlDelay)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	host.lastAccess[userAgent] = next
+	c.mu.Unlock()
+	return sleep(ctx, time.Until(next))
+}
+
+// Allowed is like Check but does not wait for the Crawl-delay, e.g. when a HostLimiter spaces the requests
+func (c *RobotsChecker) Allowed(ctx context.Context, targetURL string, userAgent string) error {
+	_, _, err := c.check(ctx, targetURL, userAgent)
+	return err
+}
+
+// check returns the host of the URL and the rules of the user agent, or a RobotsDisallowedError.
+// The host is nil for the robots.txt files themselves
+func (c *RobotsChecker) check(ctx context.Context, targetURL string, userAgent string) (*robotsHost, *robotsGroup, error) {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not parse "+targetURL)
+	}
+	if u.Path == "/robots.txt" {
+		return nil, nil, nil
+	}
+
+	host, err := c.host(ctx, u, userAgent)
+	if err != nil {
+		return nil, nil, err
+	}
+	group := host.rules.group(userAgent)
+	if !group.allowed(u.RequestURI()) {
+		return nil, nil, &RobotsDisallowedError{URL: targetURL, UserAgent: userAgent}
+	}
+	return host, group, nil
+}
+
+// CrawlDelay returns the Crawl-delay robots.txt asks the user agent to wait between two requests on the host of the URL
+func (c *RobotsChecker) CrawlDelay(ctx context.Context, targetURL string, userAgent string) (time.Duration, error) {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not parse "+targetURL)
+	}
+	host, err := c.host(ctx, u, userAgent)
+	if err != nil {
+		return 0, err
+	}
+	return host.rules.group(userAgent).crawlDelay, nil
+}
+
+// host returns the robots.txt rules of the host of the URL, fetching them when needed
+func (c *RobotsChecker) host(ctx context.Context, u *url.URL, userAgent string) (*robotsHost, error) {
+	key := u.Scheme + "://" + u.Host
+	for {
+		c.mu.Lock()
+		host, ok := c.hosts[key]
+		if !ok || (isClosed(host.ready) && time.Now().After(host.expires)) {
+			previous := host
+			host = &robotsHost{ready: make(chan struct{}), lastAccess: make(map[string]time.Time)}
+			if previous != nil {
+				host.lastAccess = previous.lastAccess
+			}
+			c.hosts[key] = host
+			c.mu.Unlock()
+
+			rules, expires, err := c.fetch(ctx, key+"/robots.txt", userAgent)
+			c.mu.Lock()
+			if err != nil {
+				// the caller gave up, let the next one fetch the file
+				delete(c.hosts, key)
+			}
+			host.rules, host.expires = rules, expires
+			c.mu.Unlock()
+			close(host.ready)
+			if err != nil {
+				return nil, err
+			}
+			return host, nil
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-host.ready:
+			if host.rules != nil {
+				return host, nil
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// fetch retrieves and parses a robots.txt file. As recommended by RFC 9309, a missing
+// file allows everything whilst an unreachable one disallows everything for a while.
+// Only the errors of the context are returned
+func (c *RobotsChecker) fetch(ctx context.Context, robotsURL string, userAgent string) (*robotsRules, time.Time, error) {
+	f := c.fetcher
+	if f == nil {
+		httpFetcher := NewHTTPFetcher(c.client, userAgent)
+		httpFetcher.MaxBodySize = robotsMaxSize
+		f = httpFetcher
+	}
+	result, err := f.Fetch(ctx, robotsURL)
+	if ctx.Err() != nil {
+		return nil, time.Time{}, ctx.Err()
+	}
+	var fetchErr *FetchError
+	switch {
+	case err == nil && result.StatusCode < http.StatusBadRequest:
+		return parseRobots(result.Body), time.Now().Add(robotsTTL), nil
+	case err == nil && result.StatusCode < http.StatusInternalServerError,
+		errors.As(err, &fetchErr) && fetchErr.StatusCode >= http.StatusBadRequest && fetchErr.StatusCode < http.StatusInternalServerError:
+		return &robotsRules{}, time.Now().Add(robotsTTL), nil
+	}
+	return &robotsRules{disallowAll: true}, time.Now().Add(robotsFailureTTL), nil
+}
+
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// robotsRules are the parsed groups of a robots.txt file
+type robotsRules struct {
+	groups      []*robotsGroup
+	disallowAll bool
+}
+
+type robotsGroup struct {
+	agents     []string
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+	matcher *regexp.Regexp
+}
+
+// newRobotsRule compiles the pattern of a rule, where * matches any sequence
+// of characters and a trailing $ anchors the end of the path
+func newRobotsRule(allow bool, pattern string) robotsRule {
+	expr := regexp.QuoteMeta(strings.TrimSuffix(pattern, "$"))
+	expr = "^" + strings.Replace(expr, `\*`, ".*", -1)
+	if strings.HasSuffix(pattern, "$") {
+		expr += "$"
+	}
+	return robotsRule{allow: allow, pattern: pattern, matcher: regexp.MustCompile(expr)}
+}
+
+// parseRobots parses a robots.txt file into its groups of rules
+func parseRobots(body []byte) *robotsRules {
+	rules := new(robotsRules)
+	var group *robotsGroup
+	lastWasAgent := false
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "user-agent" {
+			// consecutive user-agent lines share the same group
+			if !lastWasAgent || group == nil {
+				group = new(robotsGroup)
+				rules.groups = append(rules.groups, group)
+			}
+			group.agents = append(group.agents, strings.ToLower(value))
+			lastWasAgent = true
+			continue
+		}
+		lastWasAgent = false
+		if group == nil {
+			continue
+		}
+		switch key {
+		case "allow", "disallow":
+			if value != "" {
+				group.rules = append(group.rules, newRobotsRule(key == "allow", value))
+			}
+		case "crawl-delay":
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				group.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+	return rules
+}
+
+// group returns the rules applying to the user agent: those of the groups naming its
+// product token, or else those of the * groups
+func (r *robotsRules) group(userAgent string) *robotsGroup {
+	if r.disallowAll {
+		return &robotsGroup{rules: []robotsRule{newRobotsRule(false, "/")}}
+	}
+	token := productToken(userAgent)
+	best := "*"
+	for _, group := range r.groups {
+		for _, agent := range group.agents {
+			if agent != "*" && agent == token {
+				best = agent
+			}
+		}
+	}
+
+	matched := new(robotsGroup)
+	for _, group := range r.groups {
+		for _, agent := range group.agents {
+			if agent == best {
+				matched.rules = append(matched.rules, group.rules...)
+				if group.crawlDelay > matched.crawlDelay {
+					matched.crawlDelay = group.crawlDelay
+				}
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// productToken returns the product token of a user agent in lower case, e.g. "goosebot" for
+// "GooseBot/1.0 (+https://example.com/bot)", which RFC 9309 matches against the User-agent lines
+func productToken(userAgent string) string {
+	token := strings.TrimSpace(userAgent)
+	if i := strings.IndexAny(token, "/ "); i >= 0 {
+		token = token[:i]
+	}
+	return strings.ToLower(token)
+}
+
+// allowed applies the most specific (longest) matching rule, allow winning ties
+func (g *robotsGroup) allowed(path string) bool {
+	allowed, longest := true, -1
+	for _, rule := range g.rules {
+		if !rule.matcher.MatchString(path) {
+			continue
+		}
+		if len(rule.pattern) > longest || (len(rule.pattern) == longest && rule.allow) {
+			allowed, longest = rule.allow, len(rule.pattern)
+		}
+	}
+	return allowed
+}
+
+// RobotsFetcher is a Fetcher checking robots.txt before delegating to another Fetcher
+type RobotsFetcher struct {
+	next      Fetcher
+	checker   *RobotsChecker
+	userAgent string
+
+	// IgnoreCrawlDelay leaves the Crawl-delay to next, e.g. a LimitedFetcher using the same checker
+	IgnoreCrawlDelay bool
+}
+
+// NewRobotsFetcher returns a Fetcher refusing the URLs robots.txt forbids to the user agent
+func NewRobotsFetcher(next Fetcher, checker *RobotsChecker, userAgent string) *RobotsFetcher {
+	return &RobotsFetcher{
+		next:      next,
+		checker:   checker,
+		userAgent: userAgent,
+	}
+}
+
+// Fetch checks robots.txt, waits for its Crawl-delay unless IgnoreCrawlDelay is set, and fetches the URL
+func (f *RobotsFetcher) Fetch(ctx context.Context, targetURL string) (*FetchResult, error) {
+	check := f.checker.Check
+	if f.IgnoreCrawlDelay {
+		check = f.checker.Allowed
+	}
+	if err := check(ctx, targetURL, f.userAgent); err != nil {
+		return nil, err
+	}
+	return f.next.Fetch(ctx, targetURL)
+}
@@ -19,7 +19,7 @@ func NewParser() *Parser {
 func replaceTagWithContents(tagSelection *goquery.Selection, collapsibleAtomTypes []atom.Atom) {
 	tagSelection.Each(func(i int, s *goquery.Selection) {
 		node := s.Get(0)
-		if node != nil && node.Type == html.ElementNode && node.DataAtom == atom.Lookup([]byte(node.Data)) {
+		if node != nil && node.Parent != nil && node.Type == html.ElementNode && node.DataAtom == atom.Lookup([]byte(node.Data)) {
 			shouldCollapse := false
 			for _, collapsibleType := range collapsibleAtomTypes {
 				if node.DataAtom == collapsibleType {
@@ -29,7 +29,9 @@ func replaceTagWithContents(tagSelection *goquery.Selection, collapsibleAtomType
 			}
 			if shouldCollapse {
 				for node.FirstChild != nil {
-					node.Parent.InsertBefore(node.FirstChild, node)
+					child := node.FirstChild
+					node.RemoveChild(child)
+					node.Parent.InsertBefore(child, node)
 				}
 				node.Parent.RemoveChild(node)
 			}
@@ -37,7 +39,8 @@ func replaceTagWithContents(tagSelection *goquery.Selection, collapsibleAtomType
 	})
 }
 
-func (p Parser) dropTag(selection *goquery.Selection) {
+// DropTag replaces the selected tags with their text contents
+func (p Parser) DropTag(selection *goquery.Selection) {
 	selection.Each(func(i int, s *goquery.Selection) {
 		replaceTagWithContents(s, whitelistedTextAtomTypes)
 	})
@@ -53,7 +56,8 @@ func (p Parser) indexOfAttribute(selection *goquery.Selection, attr string) int
 	return -1
 }
 
-func (p Parser) delAttr(selection *goquery.Selection, attr string) {
+// DelAttr removes the attribute from the first node of the selection
+func (p Parser) DelAttr(selection *goquery.Selection, attr string) {
 	idx := p.indexOfAttribute(selection, attr)
 	if idx > -1 {
 		node := selection.Get(0)
@@ -76,7 +80,8 @@ func (p Parser) clear(selection *goquery.Selection) {
 	selection.Nodes = make([]*html.Node, 0)
 }
 
-func (p Parser) removeNode(selection *goquery.Selection) {
+// RemoveNode detaches the first node of the selection from its parent
+func (p Parser) RemoveNode(selection *goquery.Selection) {
 	if selection != nil {
 		node := selection.Get(0)
 		if node != nil && node.Parent != nil {
@@ -85,7 +90,8 @@ func (p Parser) removeNode(selection *goquery.Selection) {
 	}
 }
 
-func (p Parser) name(selector string, selection *goquery.Selection) string {
+// Name returns the value of the attribute, or an empty string if it is not set
+func (p Parser) Name(selector string, selection *goquery.Selection) string {
 	value, exists := selection.Attr(selector)
 	if exists {
 		return value
@@ -93,7 +99,8 @@ func (p Parser) name(selector string, selection *goquery.Selection) string {
 	return ""
 }
 
-func (p Parser) setAttr(selection *goquery.Selection, attr string, value string) {
+// SetAttr sets (or replaces) the attribute on the first node of the selection
+func (p Parser) SetAttr(selection *goquery.Selection, attr string, value string) {
 	if selection.Size() > 0 {
 		node := selection.Get(0)
 		var attrs []html.Attribute
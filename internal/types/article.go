@@ -28,8 +28,92 @@ type Article struct {
 	Doc             *goquery.Document  `json:"-"`
 	Links           []string           `json:"links,omitempty"`
 	PublishDate     *time.Time         `json:"publishdate,omitempty"`
-	AdditionalData  map[string]string  `json:"additionaldata,omitempty"`
-	Delta           int64              `json:"delta,omitempty"`
+	ModifiedDate    *time.Time         `json:"modifieddate,omitempty"`
+	// PublishDateSource and ModifiedDateSource tell where the dates were found
+	PublishDateSource  *DateSource       `json:"publishdatesource,omitempty"`
+	ModifiedDateSource *DateSource       `json:"modifieddatesource,omitempty"`
+	AdditionalData     map[string]string `json:"additionaldata,omitempty"`
+	Delta              int64             `json:"delta,omitempty"`
+	Fetch              *FetchInfo        `json:"fetch,omitempty"`
+	Charset            string            `json:"charset,omitempty"`
+	CharsetSource      string            `json:"charsetsource,omitempty"`
+	// Metadata is the schema.org metadata read from the JSON-LD, the microdata, the RDFa or
+	// the microformats of the page, nil when there is none
+	Metadata *Metadata `json:"metadata,omitempty"`
+	// Authors are the names of the authors, with their profile URL when known
+	Authors []Entity `json:"authors,omitempty"`
+	// OpenGraph and TwitterCard hold the og:*, article:* and twitter:* meta tags, nil when there are none
+	OpenGraph   *OpenGraph   `json:"opengraph,omitempty"`
+	TwitterCard *TwitterCard `json:"twittercard,omitempty"`
+	// Citation holds the bibliographic metadata of the scholarly articles, nil for the other pages
+	Citation *Citation `json:"citation,omitempty"`
+	// Section is the section of the site the article belongs to, e.g. "Politics"
+	Section string `json:"section,omitempty"`
+	// Breadcrumbs are the links of the breadcrumb trail of the page, from the home page
+	Breadcrumbs []Breadcrumb `json:"breadcrumbs,omitempty"`
+	// Icons are the icons of the site declared by the page, then by its web app manifest when
+	// it is fetched (see Configuration.FetchWebManifest). BestIcon picks one for a given size
+	Icons []Icon `json:"icons,omitempty"`
+	// Blocks are the blocks of the main content, such as its headings, paragraphs and lists,
+	// in document order. BlocksText renders them all as plain text, and CleanedText is their
+	// text without the code, the tables and the media (see BlocksCleanedText)
+	Blocks []Block `json:"blocks,omitempty"`
+}
+
+// Breadcrumb is a link of the breadcrumb trail of a page
+type Breadcrumb struct {
+	Name string `json:"name"`
+	// URL is absolute, and empty for the current page when it is not a link
+	URL string `json:"url,omitempty"`
+}
+
+// Where the charset of the article was found, by order of precedence
+const (
+	CharsetSourceBOM   = "bom"   // byte order mark
+	CharsetSourceHTTP  = "http"  // Content-Type response header
+	CharsetSourceMeta  = "meta"  // <meta> tags
+	CharsetSourceGuess = "guess" // detected from the content itself
+)
+
+// DateSource tells where a date of the article was found, and how much it can be trusted
+type DateSource struct {
+	// Name is one of the DateSource* constants
+	Name string `json:"name"`
+	// Confidence goes from 0 to 1
+	Confidence float64 `json:"confidence"`
+}
+
+// Where the dates of the article were found, by order of precedence
+const (
+	DateSourceCitation     = "citation"     // citation_publication_date and the like
+	DateSourceJSONLD       = "jsonld"       // JSON-LD schema.org metadata
+	DateSourceOpenGraph    = "opengraph"    // article:published_time and article:modified_time
+	DateSourceMicrodata    = "microdata"    // itemprop=datePublished and itemprop=dateModified
+	DateSourceRDFa         = "rdfa"         // property=datePublished and property=dateModified
+	DateSourceMicroformats = "microformats" // dt-published and dt-updated
+	DateSourceMeta         = "meta"         // other meta tags, e.g. pubdate or dcterms.modified
+	DateSourcePubdate      = "pubdate"      // <time pubdate datetime="...">
+	DateSourceTime         = "time"         // other <time datetime="..."> elements
+	DateSourceURL          = "url"          // the URL path, e.g. /2024/05/03/
+	DateSourceText         = "text"         // the text of the page
+)
+
+// FetchInfo describes how the article was retrieved; it is only set when the
+// article was extracted from a URL
+type FetchInfo struct {
+	RequestedURL string   `json:"requestedurl,omitempty"`
+	FinalURL     string   `json:"finalurl,omitempty"`
+	Redirects    []string `json:"redirects,omitempty"`
+	StatusCode   int      `json:"statuscode,omitempty"`
+	ContentType  string   `json:"contenttype,omitempty"`
+	LastModified string   `json:"lastmodified,omitempty"`
+	ETag         string   `json:"etag,omitempty"`
+	// Retries is the number of failed attempts before the page could be fetched
+	Retries int `json:"retries,omitempty"`
+	// CacheStatus is "hit", "revalidated" or "miss" when a response cache is used
+	CacheStatus string `json:"cachestatus,omitempty"`
+	// QueueWait is the time spent waiting for the host limiter
+	QueueWait time.Duration `json:"queuewait,omitempty"`
 }
 
 // ToString is a simple method to just show the title
@@ -0,0 +1,119 @@
+package types
+
+import "strings"
+
+// Types of the content blocks
+const (
+	BlockHeading   = "heading"
+	BlockParagraph = "paragraph"
+	BlockList      = "list"
+	BlockListItem  = "item"
+	BlockQuote     = "quote"
+	BlockCode      = "code"
+	BlockTable     = "table"
+	BlockFigure    = "figure"
+	BlockEmbed     = "embed"
+)
+
+// Block is a block of the main content of the article, such as a heading or a paragraph
+type Block struct {
+	// Type is one of the Block* constants
+	Type string `json:"type"`
+	// Level is the level of a heading, from 1 to 6, or the nesting depth of a list item, from 0
+	Level int `json:"level,omitempty"`
+	// Text is the inline text of the block, the caption of a figure or a table, or the source of a code block.
+	// It holds a single line, unless a <br> breaks it
+	Text string `json:"text,omitempty"`
+	// Links are the links of the text, in order
+	Links []Link `json:"links,omitempty"`
+	// Styles are the emphasized and the code runs of the text, in order
+	Styles []Style `json:"styles,omitempty"`
+	// Ordered tells whether a list, or the list of an item, is numbered
+	Ordered bool `json:"ordered,omitempty"`
+	// Items are the items of a list, in document order. The items of the nested lists follow
+	// the item they belong to, with a greater Level
+	Items []Block `json:"items,omitempty"`
+	// Rows are the text of the cells of a table, row by row. The first row is made of
+	// headers when Header is set
+	Rows   [][]string `json:"rows,omitempty"`
+	Header bool       `json:"header,omitempty"`
+	// Language is the language of a code block, when declared (e.g. class="language-go")
+	Language string `json:"language,omitempty"`
+	// URL is the absolute URL of the image of a figure or of the source of an embed
+	URL string `json:"url,omitempty"`
+	// Alt is the alternative text of the image of a figure
+	Alt string `json:"alt,omitempty"`
+}
+
+// Link is a link of the text of a block
+type Link struct {
+	// Text is the text of the link, found at Offset (in bytes) in the text of the block
+	Text   string `json:"text"`
+	Offset int    `json:"offset"`
+	URL    string `json:"url"`
+}
+
+// Styles of the runs of text
+const (
+	StyleStrong   = "strong"   // <strong> or <b>
+	StyleEmphasis = "emphasis" // <em> or <i>
+	StyleCode     = "code"     // <code>, <kbd>, <samp> or <tt>
+)
+
+// Style is a styled run of the text of a block
+type Style struct {
+	// Type is one of the Style* constants
+	Type string `json:"type"`
+	// Text is the text of the run, found at Offset (in bytes) in the text of the block
+	Text   string `json:"text"`
+	Offset int    `json:"offset"`
+}
+
+// BlocksText renders the blocks as plain text, the blocks and the items of the lists
+// separated by blank lines, and the rows of the tables by line breaks. The figures and
+// the embeds are rendered as their caption
+func BlocksText(blocks []Block) string {
+	var texts []string
+	for _, block := range blocks {
+		if text := blockText(block); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// BlocksCleanedText renders the blocks of running text, i.e. the headings, the paragraphs,
+// the lists and the quotes, as BlocksText does. It is the CleanedText of the article
+func BlocksCleanedText(blocks []Block) string {
+	var text []Block
+	for _, block := range blocks {
+		switch block.Type {
+		case BlockHeading, BlockParagraph, BlockList, BlockQuote:
+			text = append(text, block)
+		}
+	}
+	return BlocksText(text)
+}
+
+func blockText(block Block) string {
+	var lines []string
+	switch block.Type {
+	case BlockList:
+		for _, item := range block.Items {
+			if item.Text != "" {
+				lines = append(lines, item.Text)
+			}
+		}
+		return strings.Join(lines, "\n\n")
+	case BlockTable:
+		if block.Text != "" {
+			lines = append(lines, block.Text)
+		}
+		for _, row := range block.Rows {
+			lines = append(lines, strings.Join(row, "\t"))
+		}
+	default:
+		lines = append(lines, block.Text)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,23 @@
+package types
+
+import "time"
+
+// Citation is the bibliographic metadata of a scholarly article, read from the
+// citation_* (Highwire Press), prism.* and Dublin Core meta tags of its landing page
+type Citation struct {
+	Title   string   `json:"title,omitempty"`
+	Authors []string `json:"authors,omitempty"`
+	// DOI is the bare DOI, e.g. "10.1000/xyz123", without a resolver prefix
+	DOI string `json:"doi,omitempty"`
+	// Journal is the title of the journal, or of the conference or the book
+	Journal         string     `json:"journal,omitempty"`
+	Publisher       string     `json:"publisher,omitempty"`
+	ISSN            string     `json:"issn,omitempty"`
+	Volume          string     `json:"volume,omitempty"`
+	Issue           string     `json:"issue,omitempty"`
+	FirstPage       string     `json:"firstpage,omitempty"`
+	LastPage        string     `json:"lastpage,omitempty"`
+	PublicationDate *time.Time `json:"publicationdate,omitempty"`
+	// PDFURL is the absolute URL of the full text in PDF
+	PDFURL string `json:"pdfurl,omitempty"`
+}
@@ -1,16 +1,23 @@
 package types
 
 import (
+	"context"
+	"net/http"
 	"time"
-	"github.com/advancedlogic/GoOse/pkg/goose"
+
+	"github.com/advancedlogic/GoOse/internal/fetcher"
+	"github.com/advancedlogic/GoOse/internal/parser"
+	"github.com/advancedlogic/GoOse/internal/utils"
 )
 
 const defaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_7_2) AppleWebKit/534.52.7 (KHTML, like Gecko) Version/5.1.2 Safari/534.52.7"
 
 // Configuration is a wrapper for various config options
 type Configuration struct {
-	localStoragePath        string //not used in this version
-	imagesMinBytes          int    //not used in this version
+	// LocalStoragePath is the directory of the on-disk response cache, used along an
+	// in-memory one when Cache is not set (no cache when empty)
+	LocalStoragePath        string
+	imagesMinBytes          int //not used in this version
 	TargetLanguage          string
 	imageMagickConvertPath  string //not used in this version
 	imageMagickIdentifyPath string //not used in this version
@@ -23,17 +30,69 @@ type Configuration struct {
 
 	//path to the stopwords folder
 	stopWordsPath string
-	StopWords     goose.StopWords
-	Parser        *goose.Parser
+	StopWords     utils.StopWords
+	Parser        *parser.Parser
 
 	Timeout time.Duration
+
+	// Fetcher retrieves the pages for ExtractFromURL. When nil, an HTTP fetcher
+	// honouring Timeout and BrowserUserAgent is used
+	Fetcher fetcher.Fetcher
+	// HTTPClient is the client of the default fetcher, e.g. to add a proxy, TLS settings or
+	// instrumentation through its Transport. When nil, NewWithConfig builds one honouring
+	// Timeout and CookieJar, shared by all the extractions of the instance
+	HTTPClient *http.Client
+	// MaxMetaRefreshHops is the number of <meta http-equiv="refresh"> redirects
+	// followed by ExtractFromURL (0 disables them)
+	MaxMetaRefreshHops int
+	// MaxBodySize and MaxDecompressedSize cap, in bytes, the responses read by the
+	// default fetcher before and after decompression (0 means no limit)
+	MaxBodySize         int64
+	MaxDecompressedSize int64
+	// ContentHandlers handle the non-HTML documents, keyed by media type (e.g. "application/pdf").
+	// Documents of any other type are rejected with an UnsupportedContentTypeError
+	ContentHandlers map[string]ContentHandler
+	// RetryPolicy tells which failed requests the default fetcher tries again. The zero
+	// value, the default, makes a single attempt; DefaultRetryPolicy retries the usual
+	// transient failures
+	RetryPolicy fetcher.RetryPolicy
+	// Cache stores the responses of the default fetcher and revalidates them once stale
+	Cache fetcher.Cache
+	// Robots, when set, is checked before fetching any page, and the pages robots.txt
+	// disallows are refused with a RobotsDisallowedError. It can be shared between instances
+	Robots *fetcher.RobotsChecker
+	// RobotsUserAgent is the crawler token matched against the robots.txt rules,
+	// BrowserUserAgent when empty
+	RobotsUserAgent string
+	// Limiter, when set, throttles the requests sent to each host, spacing them by the
+	// Crawl-delay of robots.txt when Robots is set. It can be shared between instances
+	Limiter *fetcher.HostLimiter
+	// CookieJar keeps the cookies of the default fetcher, e.g. a PersistentCookieJar to
+	// remember them between runs. When nil, they are kept in memory by the instance.
+	// It is ignored when HTTPClient is set
+	CookieJar http.CookieJar
+	// ConsentCookies are preset for each domain (and its subdomains) to get past the
+	// consent interstitials, e.g. {"bbc.co.uk": {{Name: "ckns_policy", Value: "111"}}}
+	ConsentCookies map[string][]*http.Cookie
+	// RequireContent makes the extraction fail with ErrNoContent when no main content
+	// is found, instead of returning an article with the metadata only
+	RequireContent bool
+	// TrackingParams are the query parameters removed from the canonical link, e.g. "fbclid"
+	// or "utm_*" for all those starting with utm_ (see utils.DefaultTrackingParams)
+	TrackingParams []string
+	// FetchWebManifest makes the extraction fetch the web app manifest linked by the page,
+	// with Fetcher or the default fetcher, to add its icons to Article.Icons
+	FetchWebManifest bool
 }
 
+// ContentHandler builds an article from a fetched document that is not HTML
+type ContentHandler func(ctx context.Context, result *fetcher.FetchResult) (*Article, error)
+
 // GetDefaultConfiguration returns safe default configuration options
 func GetDefaultConfiguration(args ...string) Configuration {
 	if len(args) == 0 {
 		return Configuration{
-			localStoragePath:        "",   //not used in this version
+			LocalStoragePath:        "",
 			imagesMinBytes:          4500, //not used in this version
 			EnableImageFetching:     true,
 			UseMetaLanguage:         true,
@@ -45,13 +104,17 @@ func GetDefaultConfiguration(args ...string) Configuration {
 			ExtractPublishDate:      true,
 			AdditionalDataExtractor: false,
 			stopWordsPath:           "resources/stopwords",
-			StopWords:               goose.NewStopwords(), //TODO with path
-			Parser:                  goose.NewParser(),
+			StopWords:               utils.NewStopwords(), //TODO with path
+			Parser:                  parser.NewParser(),
 			Timeout:                 time.Duration(5 * time.Second),
+			MaxMetaRefreshHops:      3,
+			MaxBodySize:             10 << 20,
+			MaxDecompressedSize:     20 << 20,
+			TrackingParams:          append([]string(nil), utils.DefaultTrackingParams...),
 		}
 	}
 	return Configuration{
-		localStoragePath:        "",   //not used in this version
+		LocalStoragePath:        "",
 		imagesMinBytes:          4500, //not used in this version
 		EnableImageFetching:     true,
 		UseMetaLanguage:         true,
@@ -63,8 +126,12 @@ func GetDefaultConfiguration(args ...string) Configuration {
 		ExtractPublishDate:      true,
 		AdditionalDataExtractor: false,
 		stopWordsPath:           "resources/stopwords",
-		StopWords:               goose.NewStopwords(), //TODO with path
-		Parser:                  goose.NewParser(),
+		StopWords:               utils.NewStopwords(), //TODO with path
+		Parser:                  parser.NewParser(),
 		Timeout:                 time.Duration(5 * time.Second),
+		MaxMetaRefreshHops:      3,
+		MaxBodySize:             10 << 20,
+		MaxDecompressedSize:     20 << 20,
+		TrackingParams:          append([]string(nil), utils.DefaultTrackingParams...),
 	}
 }
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNoContent is returned when no node holding the main content could be found
+var ErrNoContent = errors.New("no content found")
+
+// Extraction phases reported by ExtractionError
+const (
+	PhasePreprocess  = "preprocess"
+	PhasePublishDate = "publish date"
+	PhaseClean       = "clean"
+	PhaseBestNode    = "best node"
+	PhaseFormat      = "output formatting"
+)
+
+// DecodeError is returned when the document cannot be converted from its charset to UTF-8
+type DecodeError struct {
+	Charset string
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("could not decode from %s: %v", e.Charset, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// ConsentRequiredError is returned when the page is a consent interstitial (cookie
+// banner) rather than the article. Provider names the consent management platform
+type ConsentRequiredError struct {
+	URL      string
+	Provider string
+}
+
+func (e *ConsentRequiredError) Error() string {
+	return fmt.Sprintf("%s is a %s consent interstitial", e.URL, e.Provider)
+}
+
+// ExtractionError is returned when a phase of the extraction fails
+type ExtractionError struct {
+	URL   string
+	Phase string
+	Err   error
+}
+
+func (e *ExtractionError) Error() string {
+	if e.URL == "" {
+		return fmt.Sprintf("%s failed: %v", e.Phase, e.Err)
+	}
+	return fmt.Sprintf("%s failed for %s: %v", e.Phase, e.URL, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ExtractionError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,94 @@
+package types
+
+import "strings"
+
+// Where the icons of the site are declared
+const (
+	IconRelIcon           = "icon"             // <link rel="icon"> or rel="shortcut icon"
+	IconRelAppleTouchIcon = "apple-touch-icon" // <link rel="apple-touch-icon">, or its -precomposed variant
+	IconRelMaskIcon       = "mask-icon"        // <link rel="mask-icon">, a monochrome SVG for the Safari pinned tabs
+	IconRelManifest       = "manifest"         // the icons of the web app manifest
+)
+
+// Icon is an icon of the site, declared by a <link> tag of the page or by its web app manifest
+type Icon struct {
+	// URL is absolute
+	URL string `json:"url"`
+	// Rel is one of the IconRel* constants
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	// Sizes are the declared sizes, empty when unknown
+	Sizes []IconSize `json:"sizes,omitempty"`
+	// Scalable is set for the vector icons (sizes="any" or SVG), which suit any size
+	Scalable bool `json:"scalable,omitempty"`
+	// Purpose is the purpose of the manifest icons, e.g. "maskable" or "monochrome"
+	Purpose string `json:"purpose,omitempty"`
+}
+
+// IconSize is a declared size of an icon, in pixels
+type IconSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// iconFit tells how well an icon suits a size, the lower the better
+type iconFit struct {
+	rank int // exact, scalable, larger, smaller, unknown size
+	gap  int // distance to the requested size in pixels
+}
+
+// BestIcon returns the icon that renders best at size×size pixels, or nil when there is none:
+// one of that size, or else a scalable one, or else the smallest larger one (which can be
+// scaled down), or else the largest smaller one. The icons that need a color to be drawn
+// (mask-icon, monochrome) or that have a safe zone (maskable) are only picked as a last resort
+func BestIcon(icons []Icon, size int) *Icon {
+	var best *Icon
+	var bestFit iconFit
+	bestSpecial := false
+	for i := range icons {
+		icon := &icons[i]
+		special := icon.Rel == IconRelMaskIcon || (icon.Purpose != "" && !hasPurpose(icon.Purpose, "any"))
+		fit := icon.fit(size)
+		if best == nil || (bestSpecial && !special) ||
+			(bestSpecial == special && (fit.rank < bestFit.rank || (fit.rank == bestFit.rank && fit.gap < bestFit.gap))) {
+			best, bestFit, bestSpecial = icon, fit, special
+		}
+	}
+	return best
+}
+
+// fit returns how well the best declared size of the icon suits the size
+func (icon *Icon) fit(size int) iconFit {
+	fit := iconFit{rank: 4}
+	if icon.Scalable {
+		fit = iconFit{rank: 1}
+	}
+	for _, declared := range icon.Sizes {
+		side := declared.Width
+		if declared.Height < side {
+			side = declared.Height
+		}
+		var candidate iconFit
+		switch {
+		case side == size:
+			candidate = iconFit{rank: 0}
+		case side > size:
+			candidate = iconFit{rank: 2, gap: side - size}
+		default:
+			candidate = iconFit{rank: 3, gap: size - side}
+		}
+		if candidate.rank < fit.rank || (candidate.rank == fit.rank && candidate.gap < fit.gap) {
+			fit = candidate
+		}
+	}
+	return fit
+}
+
+func hasPurpose(purposes string, purpose string) bool {
+	for _, p := range strings.Fields(strings.ToLower(purposes)) {
+		if p == purpose {
+			return true
+		}
+	}
+	return false
+}
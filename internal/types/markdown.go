@@ -0,0 +1,317 @@
+package types
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// markdownEscaper escapes the characters of the text that Markdown would read as inline markup
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `<`, `\<`,
+)
+
+// markdownURLEscaper escapes the characters that would end the destination of a link
+var markdownURLEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E")
+
+// markdownLineStart matches the beginnings of line that Markdown would read as a block,
+// e.g. a heading, a quote, a list item or a thematic break
+var markdownLineStart = regexp.MustCompile(`^(?:[#>+=-]|\d+[.)])`)
+
+// markdownBacktickRun matches the runs of backticks that a fence or a code span must outnumber
+var markdownBacktickRun = regexp.MustCompile("`+")
+
+// Markdown renders the article as Markdown: its title as the top heading, followed by its blocks
+func (article *Article) Markdown() string {
+	blocks := article.Blocks
+	if len(blocks) > 0 && blocks[0].Type == BlockHeading && blocks[0].Text == article.Title {
+		blocks = blocks[1:]
+	}
+	var parts []string
+	if title := strings.TrimSpace(article.Title); title != "" {
+		parts = append(parts, "# "+escapeMarkdown(title))
+	}
+	if len(blocks) == 0 && len(article.Blocks) == 0 && article.CleanedText != "" {
+		// the content handlers of the other types of documents only give the text
+		for _, paragraph := range strings.Split(article.CleanedText, "\n\n") {
+			blocks = append(blocks, Block{Type: BlockParagraph, Text: strings.TrimSpace(paragraph)})
+		}
+	}
+	if content := BlocksMarkdown(blocks); content != "" {
+		parts = append(parts, content)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+// BlocksMarkdown renders the blocks as Markdown, with their links and their styles.
+// The tables are rendered as GitHub Flavored Markdown tables
+func BlocksMarkdown(blocks []Block) string {
+	var parts []string
+	for _, block := range blocks {
+		if part := blockMarkdown(block); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func blockMarkdown(block Block) string {
+	switch block.Type {
+	case BlockHeading:
+		level := block.Level
+		if level < 1 || level > 6 {
+			level = 2
+		}
+		// a heading set in bold throughout is bold already
+		var styles []Style
+		for _, style := range block.Styles {
+			if style.Type != StyleStrong || style.Text != block.Text {
+				styles = append(styles, style)
+			}
+		}
+		block.Styles = styles
+		text := strings.Replace(inlineMarkdown(block), "\n", " ", -1)
+		return strings.Repeat("#", level) + " " + text
+	case BlockList:
+		return listMarkdown(block.Items)
+	case BlockQuote:
+		return markdownLines(inlineMarkdown(block), "> ", "> ")
+	case BlockCode:
+		fence := "```"
+		for _, run := range markdownBacktickRun.FindAllString(block.Text, -1) {
+			if len(run) >= len(fence) {
+				fence = strings.Repeat("`", len(run)+1)
+			}
+		}
+		return fence + block.Language + "\n" + block.Text + "\n" + fence
+	case BlockTable:
+		return tableMarkdown(block)
+	case BlockFigure:
+		var lines []string
+		if block.URL != "" {
+			lines = append(lines, "!["+escapeMarkdown(block.Alt)+"]("+markdownURLEscaper.Replace(block.URL)+")")
+		}
+		if block.Text != "" {
+			lines = append(lines, markdownLines(inlineMarkdown(block), "", ""))
+		}
+		return strings.Join(lines, "\n\n")
+	case BlockEmbed:
+		if block.URL == "" {
+			return markdownLines(inlineMarkdown(block), "", "")
+		}
+		source := markdownURLEscaper.Replace(block.URL)
+		if block.Text == "" {
+			return "<" + source + ">"
+		}
+		return "[" + strings.Replace(escapeMarkdown(block.Text), "\n", " ", -1) + "](" + source + ")"
+	default:
+		return markdownLines(inlineMarkdown(block), "", "")
+	}
+}
+
+// listMarkdown renders the items of a list, indenting the nested ones under their parent
+func listMarkdown(items []Block) string {
+	var lines []string
+	// indents are those of the items of each level, under the last item of the level above,
+	// and numbers are the numbers of the last items of each level
+	var indents []string
+	var numbers []int
+	for _, item := range items {
+		level := item.Level
+		if level < 0 {
+			level = 0
+		}
+		for len(indents) <= level {
+			indent := ""
+			if n := len(indents); n > 0 {
+				indent = indents[n-1] + "  "
+			}
+			indents = append(indents, indent)
+			numbers = append(numbers, 0)
+		}
+		// the nested lists of the previous item end here
+		indents, numbers = indents[:level+1], numbers[:level+1]
+		marker := "-"
+		if item.Ordered {
+			numbers[level]++
+			marker = strconv.Itoa(numbers[level]) + "."
+		}
+		prefix := indents[level] + marker + " "
+		content := strings.Repeat(" ", len(prefix))
+		lines = append(lines, markdownLines(inlineMarkdown(item), prefix, content))
+		indents, numbers = append(indents, content), append(numbers, 0)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tableMarkdown renders a table after its caption. Markdown requires a header, which is left
+// blank when the table has none
+func tableMarkdown(block Block) string {
+	columns := 0
+	for _, row := range block.Rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	var lines []string
+	if block.Text != "" {
+		lines = append(lines, markdownLines(inlineMarkdown(block), "", ""), "")
+	}
+	if columns == 0 {
+		return strings.Join(lines, "\n")
+	}
+	row := func(cells []string) string {
+		var line strings.Builder
+		line.WriteString("|")
+		for i := 0; i < columns; i++ {
+			cell := ""
+			if i < len(cells) {
+				cell = strings.Join(strings.Fields(cells[i]), " ")
+			}
+			line.WriteString(" " + strings.Replace(escapeMarkdown(cell), "|", `\|`, -1) + " |")
+		}
+		return line.String()
+	}
+	rows := block.Rows
+	if block.Header {
+		lines = append(lines, row(rows[0]))
+		rows = rows[1:]
+	} else {
+		lines = append(lines, row(nil))
+	}
+	lines = append(lines, "|"+strings.Repeat(" --- |", columns))
+	for _, cells := range rows {
+		lines = append(lines, row(cells))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markdownLines prefixes the lines of a rendered text, the first one and the others, joining
+// them with hard line breaks
+func markdownLines(text string, first string, others string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if markdownLineStart.MatchString(line) {
+			line = escapeLineStart(line)
+		}
+		if i == 0 {
+			lines[i] = first + line
+		} else {
+			lines[i] = others + line
+		}
+	}
+	return strings.Join(lines, "\\\n")
+}
+
+// escapeLineStart escapes the characters that make a line read as a block
+func escapeLineStart(line string) string {
+	digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+	if digits > 0 {
+		// an ordered list item, e.g. "1984. A year"
+		return line[:digits] + `\` + line[digits:]
+	}
+	return `\` + line
+}
+
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// markdownSpan is a link or a style of a text, rendered between its opening and closing markup
+type markdownSpan struct {
+	start, end int
+	open       string
+	close      string
+	code       bool
+}
+
+// inlineMarkdown renders the text of a block with its links and its styles. The spans that
+// overlap a previous one without nesting in it are left out, as are those within code
+func inlineMarkdown(block Block) string {
+	text := block.Text
+	var spans []markdownSpan
+	for _, link := range block.Links {
+		if end := link.Offset + len(link.Text); link.Offset >= 0 && end <= len(text) && link.Text != "" &&
+			text[link.Offset:end] == link.Text {
+			spans = append(spans, markdownSpan{start: link.Offset, end: end, open: "[",
+				close: "](" + markdownURLEscaper.Replace(link.URL) + ")"})
+		}
+	}
+	for _, style := range block.Styles {
+		end := style.Offset + len(style.Text)
+		if style.Offset < 0 || end > len(text) || style.Text == "" || text[style.Offset:end] != style.Text {
+			continue
+		}
+		span := markdownSpan{start: style.Offset, end: end}
+		switch style.Type {
+		case StyleStrong:
+			span.open, span.close = "**", "**"
+		case StyleEmphasis:
+			span.open, span.close = "*", "*"
+		case StyleCode:
+			span.code = true
+			fence := "`"
+			for _, run := range markdownBacktickRun.FindAllString(style.Text, -1) {
+				if len(run) >= len(fence) {
+					fence = strings.Repeat("`", len(run)+1)
+				}
+			}
+			span.open, span.close = fence, fence
+			if strings.HasPrefix(style.Text, "`") || strings.HasSuffix(style.Text, "`") {
+				span.open, span.close = fence+" ", " "+fence
+			}
+		default:
+			continue
+		}
+		spans = append(spans, span)
+	}
+	// the outer spans first, and the links around the styles of the same text
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end > spans[j].end
+	})
+
+	var out strings.Builder
+	var stack []markdownSpan
+	position := 0
+	write := func(end int) {
+		if len(stack) > 0 && stack[len(stack)-1].code {
+			out.WriteString(text[position:end])
+		} else {
+			out.WriteString(escapeMarkdown(text[position:end]))
+		}
+		position = end
+	}
+	closeUntil := func(offset int) {
+		for len(stack) > 0 && stack[len(stack)-1].end <= offset {
+			span := stack[len(stack)-1]
+			write(span.end)
+			out.WriteString(span.close)
+			stack = stack[:len(stack)-1]
+		}
+	}
+	for _, span := range spans {
+		closeUntil(span.start)
+		if len(stack) > 0 && (stack[len(stack)-1].code || span.end > stack[len(stack)-1].end) {
+			continue
+		}
+		if span.open == "[" && span.start > position && text[span.start-1] == '!' {
+			// not an image
+			write(span.start - 1)
+			out.WriteString(`\!`)
+			position = span.start
+		}
+		write(span.start)
+		out.WriteString(span.open)
+		stack = append(stack, span)
+	}
+	closeUntil(len(text))
+	write(len(text))
+	return out.String()
+}
@@ -0,0 +1,90 @@
+package types
+
+import "time"
+
+// Formats of the structured metadata, by order of precedence
+const (
+	MetadataSourceJSONLD       = "jsonld"       // <script type="application/ld+json">
+	MetadataSourceMicrodata    = "microdata"    // itemscope, itemtype and itemprop attributes
+	MetadataSourceRDFa         = "rdfa"         // vocab, typeof and property attributes
+	MetadataSourceMicroformats = "microformats" // h-entry and h-card classes, or hentry and vcard
+)
+
+// Metadata is the schema.org metadata describing the article, e.g. the NewsArticle
+// object of its JSON-LD, or the same read from its microdata, RDFa or microformats
+type Metadata struct {
+	// Type is the schema.org type of the article, e.g. "NewsArticle", or "h-entry" for the microformats
+	Type          string     `json:"type,omitempty"`
+	Headline      string     `json:"headline,omitempty"`
+	Authors       []Entity   `json:"authors,omitempty"`
+	DatePublished *time.Time `json:"datepublished,omitempty"`
+	DateModified  *time.Time `json:"datemodified,omitempty"`
+	// DatePublishedSource and DateModifiedSource are the formats the dates were read from
+	DatePublishedSource string  `json:"datepublishedsource,omitempty"`
+	DateModifiedSource  string  `json:"datemodifiedsource,omitempty"`
+	Publisher           *Entity `json:"publisher,omitempty"`
+	// Image is the absolute URL of the main image
+	Image          string   `json:"image,omitempty"`
+	ArticleSection []string `json:"articlesection,omitempty"`
+	Keywords       []string `json:"keywords,omitempty"`
+	// IsAccessibleForFree is nil when the page does not tell, false for paywalled articles
+	IsAccessibleForFree *bool `json:"isaccessibleforfree,omitempty"`
+	// Sources are the formats (MetadataSource*) the metadata was read from, by order of precedence
+	Sources []string `json:"sources,omitempty"`
+}
+
+// MergeMetadata merges the metadata read from several formats, given by order of precedence:
+// each field is taken from the first of them having it. It returns nil when all of them are nil
+func MergeMetadata(sources ...*Metadata) *Metadata {
+	var merged *Metadata
+	for _, source := range sources {
+		if source == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &Metadata{}
+		}
+		if merged.Type == "" {
+			merged.Type = source.Type
+		}
+		if merged.Headline == "" {
+			merged.Headline = source.Headline
+		}
+		if len(merged.Authors) == 0 {
+			merged.Authors = source.Authors
+		}
+		if merged.DatePublished == nil && source.DatePublished != nil {
+			merged.DatePublished, merged.DatePublishedSource = source.DatePublished, source.DatePublishedSource
+		}
+		if merged.DateModified == nil && source.DateModified != nil {
+			merged.DateModified, merged.DateModifiedSource = source.DateModified, source.DateModifiedSource
+		}
+		if merged.Publisher == nil {
+			merged.Publisher = source.Publisher
+		}
+		if merged.Image == "" {
+			merged.Image = source.Image
+		}
+		if len(merged.ArticleSection) == 0 {
+			merged.ArticleSection = source.ArticleSection
+		}
+		if len(merged.Keywords) == 0 {
+			merged.Keywords = source.Keywords
+		}
+		if merged.IsAccessibleForFree == nil {
+			merged.IsAccessibleForFree = source.IsAccessibleForFree
+		}
+		merged.Sources = append(merged.Sources, source.Sources...)
+	}
+	return merged
+}
+
+// Entity is a person or an organization, e.g. an author or a publisher
+type Entity struct {
+	// Type is the schema.org type of the entity, e.g. "Person" or "Organization"
+	Type string `json:"type,omitempty"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+	// Logo is the absolute URL of the logo of an organization
+	Logo string `json:"logo,omitempty"`
+}
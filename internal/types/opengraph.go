@@ -0,0 +1,42 @@
+package types
+
+import "time"
+
+// OpenGraph holds the Open Graph properties of the page (https://ogp.me)
+type OpenGraph struct {
+	Type        string `json:"type,omitempty"`
+	SiteName    string `json:"sitename,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+	// PublishedTime, ModifiedTime, Authors, Section and Tags are the article:* properties
+	PublishedTime *time.Time       `json:"publishedtime,omitempty"`
+	ModifiedTime  *time.Time       `json:"modifiedtime,omitempty"`
+	Authors       []string         `json:"authors,omitempty"`
+	Section       string           `json:"section,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+	Images        []OpenGraphImage `json:"images,omitempty"`
+}
+
+// OpenGraphImage is an og:image with its structured properties
+type OpenGraphImage struct {
+	URL       string `json:"url,omitempty"`
+	SecureURL string `json:"secureurl,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+	Alt       string `json:"alt,omitempty"`
+}
+
+// TwitterCard holds the Twitter Card properties of the page
+type TwitterCard struct {
+	// Card is the type of card, e.g. "summary_large_image"
+	Card        string `json:"card,omitempty"`
+	Site        string `json:"site,omitempty"`
+	Creator     string `json:"creator,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	ImageAlt    string `json:"imagealt,omitempty"`
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	"github.com/pkg/errors"
 	"golang.org/x/net/html/charset"
 	"golang.org/x/text/transform"
 )
@@ -51,13 +52,55 @@ func NormaliseCharset(characterSet string) string {
 	return characterSet
 }
 
-// UTF8encode converts a string from the source character set to UTF-8, skipping invalid byte sequences
-// @see http://stackoverflow.com/questions/32512500/ignore-illegal-bytes-when-decoding-text-with-go
+// byteOrderMarks maps the byte order marks to the charset they announce
+var byteOrderMarks = []struct {
+	bom     string
+	charset string
+}{
+	{"\xEF\xBB\xBF", "UTF-8"},
+	{"\xFE\xFF", "UTF-16BE"},
+	{"\xFF\xFE", "UTF-16LE"},
+}
+
+// BOMCharset returns the charset announced by the byte order mark at the start
+// of the content, along with the length of the mark; an empty string if there is none
+func BOMCharset(raw string) (string, int) {
+	for _, mark := range byteOrderMarks {
+		if strings.HasPrefix(raw, mark.bom) {
+			return mark.charset, len(mark.bom)
+		}
+	}
+	return "", 0
+}
+
+// GuessCharset returns a best-effort charset for content that does not declare one
+func GuessCharset(raw string) string {
+	characterSet, _ := DetectCharset(raw)
+	return characterSet
+}
+
+// IsSupportedCharset tells whether content in the character set can be converted to UTF-8
+func IsSupportedCharset(sourceCharset string) bool {
+	enc, _ := charset.Lookup(whatwgLabel(sourceCharset))
+	return enc != nil
+}
+
+// UTF8encode converts a string from the source character set to UTF-8, skipping invalid byte sequences.
+// The string is returned unchanged if the character set is not supported
 func UTF8encode(raw string, sourceCharset string) string {
-	enc, name := charset.Lookup(sourceCharset)
+	converted, err := ConvertToUTF8(raw, sourceCharset)
+	if err != nil {
+		log.Println(err)
+	}
+	return converted
+}
+
+// ConvertToUTF8 converts a string from the source character set to UTF-8, skipping invalid byte sequences
+// @see http://stackoverflow.com/questions/32512500/ignore-illegal-bytes-when-decoding-text-with-go
+func ConvertToUTF8(raw string, sourceCharset string) (string, error) {
+	enc, _ := charset.Lookup(whatwgLabel(sourceCharset))
 	if nil == enc {
-		log.Println("Cannot convert from", sourceCharset, ":", name)
-		return raw
+		return raw, errors.Errorf("cannot convert from unsupported charset %q", sourceCharset)
 	}
 
 	dst := make([]byte, len(raw))
@@ -88,5 +131,5 @@ func UTF8encode(raw string, sourceCharset string) string {
 		_, width := utf8.DecodeRuneInString(raw[in:])
 		in += width
 	}
-	return string(dst)
+	return string(dst), nil
 }
@@ -0,0 +1,241 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// detectionSampleSize caps the number of bytes inspected by the charset detector
+const detectionSampleSize = 64 * 1024
+
+// charsetModel describes a legacy charset by the characters that are frequent
+// in the languages usually written with it
+type charsetModel struct {
+	charset  string // normalised name, as returned by NormaliseCharset
+	encoding encoding.Encoding
+	frequent func(r rune) bool
+}
+
+var multiByteModels = []charsetModel{
+	{"SHIFT_JIS", japanese.ShiftJIS, isKana},
+	{"EUC-JP", japanese.EUCJP, isKana},
+	{"UHC", korean.EUCKR, isFrequentHangul},
+	{"GB18030", simplifiedchinese.GB18030, isFrequentHanzi},
+	{"BIG5", traditionalchinese.Big5, isFrequentHanzi},
+}
+
+var singleByteModels = []charsetModel{
+	{"KOI8-R", charmap.KOI8R, isFrequentCyrillic},
+	{"CP1251", charmap.Windows1251, isFrequentCyrillic},
+}
+
+// the most frequent characters in Korean, Chinese (simplified and traditional) and Russian texts
+var (
+	frequentHangul   = runeSet("이다는의에가을하고로한서지기사리도어자들으나니를것라대인수시일게해주면그아정적있보여만구부요무제상거우내말없같되중때전성생장원동과와은서며음습세경터까")
+	frequentHanzi    = runeSet("的一是不了人我在有他这中大来上国个到说们为子和你地出道也时年得就那要下以生会自着去之过家学对可她里后小么心多天而能好都然没日于起还发成事只作当想看文无开手十用主行方又如前所本见经头面公同三已老从动两长知民样现分将外但身些与高意进把法此实回二理美点月明其种声全工己话儿者向情部正名定女问力机给等几很业最间新什打便位因重被走电四第门相次东政海口使教西再平真听世气信北少关并内加化由却代军产入先山五太水万市眼体别处总才场师书比住员九笑性通目华报立马命张活难神数件安表原车白应路期叫死常提感金何更反合放做系计或司利受这們國個來說為會時對發後還過經學見長麼裡開從動兩現將實點種聲話問機給幾業間們電門東長頭無與應關進")
+	frequentCyrillic = runeSet("оеаинтсрвлкмдпу")
+)
+
+func runeSet(chars string) map[rune]bool {
+	set := make(map[rune]bool)
+	for _, r := range chars {
+		set[r] = true
+	}
+	return set
+}
+
+// isKana ignores the half-width katakana, which are seldom used and which are
+// what single-byte Cyrillic text turns into when decoded as Shift_JIS
+func isKana(r rune) bool {
+	if r >= 0xFF00 && r <= 0xFFEF {
+		return false
+	}
+	return unicode.In(r, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
+
+func isFrequentHangul(r rune) bool {
+	return frequentHangul[r]
+}
+
+func isFrequentHanzi(r rune) bool {
+	return frequentHanzi[r]
+}
+
+func isFrequentCyrillic(r rune) bool {
+	return frequentCyrillic[r]
+}
+
+// score decodes the sample and returns the share of its non-ASCII characters
+// that are frequent in the model languages, along with the share of invalid sequences
+func (m charsetModel) score(sample string) (float64, float64) {
+	decoded, err := m.encoding.NewDecoder().String(sample)
+	if err != nil {
+		return 0, 1
+	}
+	total, invalid, frequent := 0, 0, 0
+	for _, r := range decoded {
+		if r < utf8.RuneSelf {
+			continue
+		}
+		total++
+		if r == utf8.RuneError {
+			invalid++
+		} else if m.frequent(r) {
+			frequent++
+		}
+	}
+	if total == 0 {
+		return 0, 0
+	}
+	return float64(frequent) / float64(total), float64(invalid) / float64(total)
+}
+
+// highByteRunLength returns the average length of the runs of non-ASCII bytes.
+// Cyrillic words are made of long runs whilst accented latin letters are isolated
+func highByteRunLength(sample string) float64 {
+	high, runs := 0, 0
+	inRun := false
+	for i := 0; i < len(sample); i++ {
+		if sample[i] >= utf8.RuneSelf {
+			high++
+			if !inRun {
+				runs++
+			}
+			inRun = true
+		} else {
+			inRun = false
+		}
+	}
+	if runs == 0 {
+		return 0
+	}
+	return float64(high) / float64(runs)
+}
+
+func detectionSample(raw string) string {
+	if len(raw) > detectionSampleSize {
+		return raw[:detectionSampleSize]
+	}
+	return raw
+}
+
+// isUTF8 tells whether the sample is UTF-8, tolerating a few stray bytes (and the
+// rune cut by the sampling) since legacy text is never close to valid UTF-8
+func isUTF8(sample string) bool {
+	valid, invalid := 0, 0
+	for _, r := range sample {
+		if r == utf8.RuneError {
+			invalid++
+		} else if r >= utf8.RuneSelf {
+			valid++
+		}
+	}
+	return invalid*20 <= valid
+}
+
+func hasHighBytes(sample string) bool {
+	for i := 0; i < len(sample); i++ {
+		if sample[i] >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectCharset guesses the charset of the content from the frequency of its bytes.
+// It recognises UTF-8, Shift_JIS, EUC-JP, EUC-KR, GB18030, Big5, KOI8-R and windows-1251,
+// and falls back to windows-1252. It returns the normalised charset name along with
+// a confidence between 0 and 1
+func DetectCharset(raw string) (string, float64) {
+	sample := detectionSample(raw)
+	if !hasHighBytes(sample) || isUTF8(sample) {
+		return "UTF-8", 1
+	}
+
+	best, confidence := "", 0.0
+	for _, model := range multiByteModels {
+		frequent, invalid := model.score(sample)
+		if invalid > 0.02 {
+			continue
+		}
+		if frequent > confidence {
+			best, confidence = model.charset, frequent
+		}
+	}
+	if confidence >= 0.15 {
+		return best, confidence
+	}
+
+	if highByteRunLength(sample) >= 2 {
+		best, confidence = "", 0.0
+		for _, model := range singleByteModels {
+			if frequent, _ := model.score(sample); frequent > confidence {
+				best, confidence = model.charset, frequent
+			}
+		}
+		if confidence >= 0.2 {
+			return best, confidence
+		}
+	}
+
+	// same fallback as the browsers
+	return "CP1252", 0.1
+}
+
+// IsPlausibleCharset tells whether the content is likely to be encoded with the given
+// charset: content that is valid UTF-8 is not declared with a legacy charset, content
+// full of invalid sequences is not encoded with the declared charset, and content that
+// the detector confidently attributes to a close relative is not either
+func IsPlausibleCharset(raw string, characterSet string) bool {
+	sample := detectionSample(raw)
+	if !hasHighBytes(sample) {
+		return true
+	}
+	characterSet = NormaliseCharset(characterSet)
+	if characterSet == "UTF-8" || isUTF8(sample) {
+		return characterSet == "UTF-8" && isUTF8(sample)
+	}
+
+	for _, model := range append(multiByteModels, singleByteModels...) {
+		if !sameCharset(model.charset, characterSet) {
+			continue
+		}
+		frequent, invalid := model.score(sample)
+		if invalid > 0.05 {
+			return false
+		}
+		detected, confidence := DetectCharset(sample)
+		return detected == model.charset || confidence < 0.3 || frequent >= confidence/3
+	}
+	return true
+}
+
+// sameCharset compares two charset names through their WHATWG encodings
+func sameCharset(a, b string) bool {
+	if a == b {
+		return true
+	}
+	_, nameA := charset.Lookup(whatwgLabel(a))
+	_, nameB := charset.Lookup(whatwgLabel(b))
+	return nameA != "" && strings.EqualFold(nameA, nameB)
+}
+
+// whatwgLabel maps the canonical names returned by NormaliseCharset that are not WHATWG labels
+func whatwgLabel(characterSet string) string {
+	switch characterSet {
+	case "UHC":
+		return "EUC-KR" // the WHATWG euc-kr encoding is actually UHC (windows-949)
+	case "LATIN-2":
+		return "ISO-8859-2"
+	}
+	return characterSet
+}
@@ -0,0 +1,524 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// dateLanguage holds the words used to write the dates in a language
+type dateLanguage struct {
+	// months and weekdays map the names, abbreviations and declensions, lowercased
+	months   map[string]time.Month
+	weekdays map[string]time.Weekday
+	// today and yesterday are the words for these days
+	today     []string
+	yesterday []string
+	// ago matches a relative date, with the named groups n (a number, 1 when it is a word) and unit
+	ago string
+	// units are the prefixes of the time units of ago
+	units map[string]dateUnit
+}
+
+type dateUnit struct {
+	years, months, days int
+	duration            time.Duration
+}
+
+var (
+	second = dateUnit{duration: time.Second}
+	minute = dateUnit{duration: time.Minute}
+	hour   = dateUnit{duration: time.Hour}
+	day    = dateUnit{days: 1}
+	week   = dateUnit{days: 7}
+	month  = dateUnit{months: 1}
+	year   = dateUnit{years: 1}
+)
+
+// monthNames builds a month map from the names of the months, each name listing its
+// spellings separated by spaces
+func monthNames(names ...string) map[string]time.Month {
+	months := make(map[string]time.Month)
+	for i, spellings := range names {
+		for _, name := range strings.Fields(spellings) {
+			months[name] = time.Month(i + 1)
+		}
+	}
+	return months
+}
+
+// weekdayNames builds a weekday map from the names of the days, from Sunday
+func weekdayNames(names ...string) map[string]time.Weekday {
+	weekdays := make(map[string]time.Weekday)
+	for i, spellings := range names {
+		for _, name := range strings.Fields(spellings) {
+			weekdays[name] = time.Weekday(i)
+		}
+	}
+	return weekdays
+}
+
+// dateLanguages covers the languages of the stop words (Sw), plus German
+var dateLanguages = map[string]*dateLanguage{
+	"en": {
+		months: monthNames("january jan", "february feb", "march mar", "april apr", "may", "june jun",
+			"july jul", "august aug", "september sep sept", "october oct", "november nov", "december dec"),
+		weekdays: weekdayNames("sunday sun", "monday mon", "tuesday tue tues", "wednesday wed",
+			"thursday thu thurs", "friday fri", "saturday sat"),
+		today:     []string{"today", "just now"},
+		yesterday: []string{"yesterday"},
+		ago:       `(?P<n>\d+|an?|one)\s+(?P<unit>\pL+)\s+ago`,
+		units: map[string]dateUnit{"sec": second, "min": minute, "hour": hour, "hr": hour, "day": day,
+			"week": week, "month": month, "year": year},
+	},
+	"es": {
+		months: monthNames("enero ene", "febrero feb", "marzo mar", "abril abr", "mayo may", "junio jun",
+			"julio jul", "agosto ago", "septiembre setiembre sep sept", "octubre oct", "noviembre nov", "diciembre dic"),
+		weekdays: weekdayNames("domingo", "lunes", "martes", "miércoles miercoles", "jueves", "viernes",
+			"sábado sabado"),
+		today:     []string{"hoy"},
+		yesterday: []string{"ayer"},
+		ago:       `hace\s+(?P<n>\d+|una?)\s+(?P<unit>\pL+)`,
+		units: map[string]dateUnit{"seg": second, "min": minute, "hora": hour, "día": day, "dia": day,
+			"semana": week, "mes": month, "año": year},
+	},
+	"fr": {
+		months: monthNames("janvier janv", "février fevrier févr fevr", "mars", "avril avr", "mai", "juin",
+			"juillet juil", "août aout", "septembre sept", "octobre oct", "novembre nov", "décembre decembre déc dec"),
+		weekdays:  weekdayNames("dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"),
+		today:     []string{"aujourd'hui", "aujourd’hui"},
+		yesterday: []string{"hier"},
+		ago:       `il y a\s+(?P<n>\d+|une?)\s+(?P<unit>\pL+)`,
+		units: map[string]dateUnit{"seconde": second, "minute": minute, "min": minute, "heure": hour, "jour": day,
+			"semaine": week, "mois": month, "an": year, "année": year},
+	},
+	"de": {
+		months: monthNames("januar jänner jan", "februar feb", "märz maerz mär", "april apr", "mai", "juni jun",
+			"juli jul", "august aug", "september sep sept", "oktober okt", "november nov", "dezember dez"),
+		weekdays: weekdayNames("sonntag", "montag", "dienstag", "mittwoch", "donnerstag", "freitag",
+			"samstag sonnabend"),
+		today:     []string{"heute"},
+		yesterday: []string{"gestern"},
+		ago:       `vor\s+(?P<n>\d+|einer?|einem)\s+(?P<unit>\pL+)`,
+		units: map[string]dateUnit{"sek": second, "min": minute, "stunde": hour, "std": hour, "tag": day,
+			"woche": week, "monat": month, "jahr": year},
+	},
+	"nl": {
+		months: monthNames("januari jan", "februari feb", "maart mrt", "april apr", "mei", "juni jun",
+			"juli jul", "augustus aug", "september sep sept", "oktober okt", "november nov", "december dec"),
+		weekdays:  weekdayNames("zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"),
+		today:     []string{"vandaag"},
+		yesterday: []string{"gisteren"},
+		ago:       `(?P<n>\d+|een)\s+(?P<unit>\pL+)\s+geleden`,
+		units: map[string]dateUnit{"seconde": second, "minu": minute, "uur": hour, "dag": day, "we": week,
+			"maand": month, "jaar": year},
+	},
+	"sv": {
+		months: monthNames("januari jan", "februari feb", "mars mar", "april apr", "maj", "juni jun",
+			"juli jul", "augusti aug", "september sep sept", "oktober okt", "november nov", "december dec"),
+		weekdays:  weekdayNames("söndag", "måndag", "tisdag", "onsdag", "torsdag", "fredag", "lördag"),
+		today:     []string{"idag", "i dag"},
+		yesterday: []string{"igår", "i går"},
+		ago:       `(?:för\s+)?(?P<n>\d+|en|ett)\s+(?P<unit>\pL+)\s+sedan`,
+		units: map[string]dateUnit{"sekund": second, "minut": minute, "timm": hour, "dag": day, "veck": week,
+			"månad": month, "år": year},
+	},
+	"ru": {
+		months: monthNames("января январь янв", "февраля февраль фев", "марта март мар", "апреля апрель апр",
+			"мая май", "июня июнь июн", "июля июль июл", "августа август авг", "сентября сентябрь сен сент",
+			"октября октябрь окт", "ноября ноябрь ноя", "декабря декабрь дек"),
+		weekdays:  weekdayNames("воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"),
+		today:     []string{"сегодня"},
+		yesterday: []string{"вчера"},
+		ago:       `(?P<n>\d+|одну|один)?\s*(?P<unit>\pL+)\s+назад`,
+		units: map[string]dateUnit{"секунд": second, "минут": minute, "час": hour, "ден": day, "дн": day,
+			"недел": week, "месяц": month, "год": year, "лет": year},
+	},
+	"bg": {
+		months: monthNames("януари яну", "февруари фев", "март мар", "април апр", "май", "юни", "юли",
+			"август авг", "септември сеп", "октомври окт", "ноември ное", "декември дек"),
+		weekdays:  weekdayNames("неделя", "понеделник", "вторник", "сряда", "четвъртък", "петък", "събота"),
+		today:     []string{"днес"},
+		yesterday: []string{"вчера"},
+		ago:       `преди\s+(?P<n>\d+|един|една)\s+(?P<unit>\pL+)`,
+		units: map[string]dateUnit{"секунд": second, "минут": minute, "час": hour, "ден": day, "дни": day,
+			"седмиц": week, "месец": month, "годин": year},
+	},
+	"sr": {
+		months: monthNames("јануар јануара januar januara", "фебруар фебруара februar februara",
+			"март марта mart marta", "април априла april aprila", "мај маја maj maja", "јун јуна jun juna",
+			"јул јула jul jula", "август августа avgust avgusta", "септембар септембра septembar septembra",
+			"октобар октобра oktobar oktobra", "новембар новембра novembar novembra", "децембар децембра decembar decembra"),
+		weekdays: weekdayNames("недеља nedelja", "понедељак ponedeljak", "уторак utorak", "среда sreda",
+			"четвртак četvrtak", "петак petak", "субота subota"),
+		today:     []string{"данас", "danas"},
+		yesterday: []string{"јуче", "juče", "juce"},
+		ago:       `(?:пре|pre)\s+(?P<n>\d+|један|jedan)\s+(?P<unit>\pL+)`,
+		units: map[string]dateUnit{"секунд": second, "sekund": second, "минут": minute, "minut": minute,
+			"сат": hour, "sat": hour, "дан": day, "dan": day, "недељ": week, "nedelj": week,
+			"месец": month, "mesec": month, "годин": year, "godin": year},
+	},
+	"id": {
+		months: monthNames("januari jan", "februari feb", "maret mar", "april apr", "mei", "juni jun",
+			"juli jul", "agustus agu agt ags", "september sep", "oktober okt", "november nov", "desember des"),
+		weekdays:  weekdayNames("minggu", "senin", "selasa", "rabu", "kamis", "jumat jum'at", "sabtu"),
+		today:     []string{"hari ini"},
+		yesterday: []string{"kemarin"},
+		ago:       `(?P<n>\d+|se)\s*(?P<unit>\pL+)\s+(?:yang\s+)?lalu`,
+		units: map[string]dateUnit{"detik": second, "menit": minute, "jam": hour, "hari": day, "minggu": week,
+			"pekan": week, "bulan": month, "tahun": year},
+	},
+	"ar": {
+		months: monthNames("يناير كانون_الثاني", "فبراير شباط", "مارس آذار", "أبريل إبريل ابريل نيسان",
+			"مايو أيار", "يونيو يونيه حزيران", "يوليو يوليه تموز", "أغسطس آب", "سبتمبر أيلول",
+			"أكتوبر اكتوبر تشرين_الأول", "نوفمبر تشرين_الثاني", "ديسمبر كانون_الأول"),
+		weekdays:  weekdayNames("الأحد", "الاثنين الإثنين", "الثلاثاء", "الأربعاء", "الخميس", "الجمعة", "السبت"),
+		today:     []string{"اليوم"},
+		yesterday: []string{"أمس", "الأمس", "امس"},
+		ago:       `منذ\s+(?P<n>\d+)?\s*(?P<unit>\pL+)`,
+		units: map[string]dateUnit{"ثاني": second, "ثوان": second, "دقيق": minute, "دقائق": minute,
+			"ساع": hour, "يوم": day, "أيام": day, "أسبوع": week, "أسابيع": week, "شهر": month, "أشهر": month,
+			"سنة": year, "سنوات": year},
+	},
+	"zh": {
+		weekdays: weekdayNames("星期日 星期天 周日", "星期一 周一", "星期二 周二", "星期三 周三",
+			"星期四 周四", "星期五 周五", "星期六 周六"),
+		today:     []string{"今天", "今日"},
+		yesterday: []string{"昨天", "昨日"},
+		ago:       `(?P<n>\d+)\s*(?P<unit>秒|分钟|分鐘|分|小时|小時|時間|天|日|周|週間|週|个月|個月|か月|ヶ月|年)前`,
+		units: map[string]dateUnit{"秒": second, "分": minute, "小时": hour, "小時": hour, "時間": hour,
+			"天": day, "日": day, "周": week, "週": week, "个月": month, "個月": month, "か月": month, "ヶ月": month,
+			"年": year},
+	},
+}
+
+// regexps shared by all the languages
+var (
+	cjkDate     = regexp.MustCompile(`(\d{4})\s*[年년]\s*(\d{1,2})\s*[月월]\s*(\d{1,2})\s*[日일号]?`)
+	isoDate     = regexp.MustCompile(`(?:^|[^\d])(\d{4})[-/](\d{1,2})[-/](\d{1,2})(?:[^\d]|$)`)
+	dottedDate  = regexp.MustCompile(`(?:^|[^\d.])(\d{1,2})\.(\d{1,2})\.(\d{4})(?:[^\d]|$)`)
+	timeOfDay   = regexp.MustCompile(`^[^\d\n]{0,12}?(\d{1,2})[:h時시](\d{2})(?::(\d{2}))?\s*(am|pm|a\.m\.|p\.m\.)?(?:\s*(?:gmt|utc)?\s*(z|[+-]\d{1,2}(?::?\d{2})?)|\s*(gmt|utc))?`)
+	offsetValue = regexp.MustCompile(`^([+-])(\d{1,2}):?(\d{2})?$`)
+)
+
+// compiledLanguage holds the regexps of a language, built once
+type compiledLanguage struct {
+	*dateLanguage
+	dayMonthYear *regexp.Regexp
+	monthDayYear *regexp.Regexp
+	relative     *regexp.Regexp
+	days         *regexp.Regexp
+}
+
+var (
+	compileOnce       sync.Once
+	compiledLanguages map[string]*compiledLanguage
+)
+
+func compileDateLanguages() {
+	compiledLanguages = make(map[string]*compiledLanguage)
+	for lang, language := range dateLanguages {
+		compiled := &compiledLanguage{dateLanguage: language}
+		if len(language.months) > 0 {
+			months := alternation(language.months)
+			compiled.dayMonthYear = regexp.MustCompile(`(?:^|[^\pL\d])(\d{1,2})(?:\.|º|°|er|st|nd|rd|th)?(?:\s+de|\s+del)?\s*(` + months +
+				`)\.?,?(?:\s+de|\s+del)?\s*(\d{4})`)
+			compiled.monthDayYear = regexp.MustCompile(`(?:^|[^\pL])(` + months + `)\.?\s+(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})`)
+		}
+		compiled.relative = regexp.MustCompile(language.ago)
+		words := append(append([]string{}, language.today...), language.yesterday...)
+		quoted := make([]string, len(words))
+		for i, word := range words {
+			quoted[i] = regexp.QuoteMeta(word)
+		}
+		compiled.days = regexp.MustCompile(`(?:^|[^\pL])(` + strings.Join(quoted, "|") + `)(?:[^\pL]|$)`)
+		compiledLanguages[lang] = compiled
+	}
+}
+
+// alternation returns a regexp alternation of the names, the longest first
+func alternation(names map[string]time.Month) string {
+	quoted := make([]string, 0, len(names))
+	for name := range names {
+		quoted = append(quoted, regexp.QuoteMeta(strings.Replace(name, "_", " ", -1)))
+	}
+	sort.Slice(quoted, func(i, j int) bool {
+		if len(quoted[i]) != len(quoted[j]) {
+			return len(quoted[i]) > len(quoted[j])
+		}
+		return quoted[i] < quoted[j]
+	})
+	return strings.Join(quoted, "|")
+}
+
+// DateParser finds the dates written in a text, in the numeric formats and in the words of
+// its language (English when the language is not known). The relative dates, such as
+// "2 hours ago", are resolved against the fetch time, and the dates without a time zone
+// are given the one of the page
+type DateParser struct {
+	language *compiledLanguage
+	now      time.Time
+	loc      *time.Location
+}
+
+// NewDateParser returns a parser for the texts in the language (e.g. "en"), fetched at the
+// given time, whose dates are in the given location (UTC when nil)
+func NewDateParser(lang string, now time.Time, loc *time.Location) *DateParser {
+	compileOnce.Do(compileDateLanguages)
+	if loc == nil {
+		loc = time.UTC
+	}
+	language, ok := compiledLanguages[lang]
+	if !ok {
+		language = compiledLanguages["en"]
+	}
+	return &DateParser{language: language, now: now.In(loc), loc: loc}
+}
+
+// IsDateLanguage tells whether the dates written in the language (e.g. "de") are recognised
+func IsDateLanguage(lang string) bool {
+	_, ok := dateLanguages[lang]
+	return ok
+}
+
+// dateMatch is a date found at a position of the text
+type dateMatch struct {
+	start, end int
+	date       time.Time
+	hasTime    bool
+}
+
+// Find returns the first absolute date written in the text, or nil when there is none
+func (p *DateParser) Find(text string) *time.Time {
+	return p.first(normaliseDigits(strings.ToLower(text)), false)
+}
+
+// FindRelative returns the first absolute date written in the text or, when there is none,
+// the first relative one such as "2 hours ago" or "yesterday". Such words are common in any
+// text, so it is meant for the short texts next to the headline, e.g. a byline
+func (p *DateParser) FindRelative(text string) *time.Time {
+	text = normaliseDigits(strings.ToLower(text))
+	if date := p.first(text, false); date != nil {
+		return date
+	}
+	return p.first(text, true)
+}
+
+// first returns the leftmost absolute date found in the text, or the leftmost relative one
+func (p *DateParser) first(text string, relative bool) *time.Time {
+	var best *dateMatch
+	consider := func(match *dateMatch) {
+		if match == nil || match.date.After(p.now.Add(24*time.Hour)) || match.date.Year() < 1900 {
+			return
+		}
+		if best == nil || match.start < best.start || (match.start == best.start && match.end > best.end) {
+			best = match
+		}
+	}
+
+	language := p.language
+	if relative {
+		for _, m := range language.relative.FindAllStringSubmatchIndex(text, -1) {
+			consider(p.relative(text, m, language))
+		}
+		for _, m := range language.days.FindAllStringSubmatchIndex(text, -1) {
+			word := text[m[2]:m[3]]
+			date := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.loc)
+			for _, yesterday := range language.yesterday {
+				if word == yesterday {
+					date = date.AddDate(0, 0, -1)
+				}
+			}
+			match := &dateMatch{start: m[2], end: m[3], date: date}
+			p.addTime(text, match)
+			consider(match)
+		}
+	} else {
+		for _, re := range []*regexp.Regexp{cjkDate, isoDate} {
+			for _, m := range re.FindAllStringSubmatchIndex(text, -1) {
+				consider(p.absolute(text, m, m[2], m[4], m[6], nil))
+			}
+		}
+		for _, m := range dottedDate.FindAllStringSubmatchIndex(text, -1) {
+			consider(p.absolute(text, m, m[6], m[4], m[2], nil))
+		}
+		if language.dayMonthYear != nil {
+			for _, m := range language.dayMonthYear.FindAllStringSubmatchIndex(text, -1) {
+				consider(p.absolute(text, m, m[6], m[4], m[2], language.months))
+			}
+			for _, m := range language.monthDayYear.FindAllStringSubmatchIndex(text, -1) {
+				consider(p.absolute(text, m, m[6], m[2], m[4], language.months))
+			}
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return &best.date
+}
+
+// absolute builds the date of a match from the positions of its year, month and day groups.
+// The month is a number when months is nil, or else one of its names
+func (p *DateParser) absolute(text string, m []int, yearAt, monthAt, dayAt int, months map[string]time.Month) *dateMatch {
+	group := func(at int) string {
+		for i := 2; i < len(m); i += 2 {
+			if m[i] == at {
+				return text[m[i]:m[i+1]]
+			}
+		}
+		return ""
+	}
+	year, _ := strconv.Atoi(group(yearAt))
+	dayOfMonth, _ := strconv.Atoi(group(dayAt))
+	var monthOfYear time.Month
+	if months == nil {
+		number, _ := strconv.Atoi(group(monthAt))
+		monthOfYear = time.Month(number)
+	} else {
+		monthOfYear = months[strings.Replace(group(monthAt), " ", "_", -1)]
+	}
+	if monthOfYear < 1 || monthOfYear > 12 || dayOfMonth < 1 {
+		return nil
+	}
+	date := time.Date(year, monthOfYear, dayOfMonth, 0, 0, 0, 0, p.loc)
+	if date.Day() != dayOfMonth {
+		return nil
+	}
+
+	start := m[2]
+	for i := 2; i < len(m); i += 2 {
+		if m[i] >= 0 && m[i] < start {
+			start = m[i]
+		}
+	}
+	match := &dateMatch{start: start, end: m[1], date: date}
+	if !weekdayMatches(text[:start], date) {
+		return nil
+	}
+	p.addTime(text, match)
+	return match
+}
+
+// relative builds the date of a relative expression such as "2 hours ago"
+func (p *DateParser) relative(text string, m []int, language *compiledLanguage) *dateMatch {
+	names := language.relative.SubexpNames()
+	amount, unitName := 1, ""
+	for i, name := range names {
+		if m[2*i] < 0 {
+			continue
+		}
+		value := text[m[2*i]:m[2*i+1]]
+		switch name {
+		case "n":
+			if number, err := strconv.Atoi(value); err == nil {
+				amount = number
+			}
+		case "unit":
+			unitName = value
+		}
+	}
+	unit, ok := language.unit(unitName)
+	if !ok {
+		return nil
+	}
+	date := p.now.AddDate(-unit.years*amount, -unit.months*amount, -unit.days*amount).Add(-unit.duration * time.Duration(amount))
+	return &dateMatch{start: m[0], end: m[1], date: date, hasTime: true}
+}
+
+// unit returns the unit whose prefix is the longest one starting the word
+func (l *compiledLanguage) unit(word string) (dateUnit, bool) {
+	best, found := "", false
+	var unit dateUnit
+	for prefix, u := range l.units {
+		if strings.HasPrefix(word, prefix) && len(prefix) > len(best) {
+			best, unit, found = prefix, u, true
+		}
+	}
+	return unit, found
+}
+
+// addTime completes the date with the time of day and the time zone following it, if any
+func (p *DateParser) addTime(text string, match *dateMatch) {
+	m := timeOfDay.FindStringSubmatch(text[match.end:])
+	if m == nil {
+		return
+	}
+	hours, _ := strconv.Atoi(m[1])
+	minutes, _ := strconv.Atoi(m[2])
+	seconds, _ := strconv.Atoi(m[3])
+	switch strings.Replace(m[4], ".", "", -1) {
+	case "pm":
+		if hours < 12 {
+			hours += 12
+		}
+	case "am":
+		if hours == 12 {
+			hours = 0
+		}
+	}
+	if hours > 23 || minutes > 59 || seconds > 59 {
+		return
+	}
+	loc := p.loc
+	switch {
+	case m[5] == "z" || m[6] != "":
+		loc = time.UTC
+	case m[5] != "":
+		if offset := offsetValue.FindStringSubmatch(m[5]); offset != nil {
+			h, _ := strconv.Atoi(offset[2])
+			min, _ := strconv.Atoi(offset[3])
+			seconds := (h*60 + min) * 60
+			if offset[1] == "-" {
+				seconds = -seconds
+			}
+			loc = time.FixedZone("", seconds)
+		}
+	}
+	d := match.date
+	match.date = time.Date(d.Year(), d.Month(), d.Day(), hours, minutes, seconds, 0, loc)
+	match.hasTime = true
+}
+
+// weekdayMatches tells whether the weekday written just before a date, if any, is the one of the date
+func weekdayMatches(before string, date time.Time) bool {
+	before = strings.TrimRightFunc(before, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+	start := strings.LastIndexFunc(before, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	word := before[start+1:]
+	if word == "" {
+		return true
+	}
+	for _, language := range compiledLanguages {
+		if weekday, ok := language.weekdays[word]; ok && weekday == date.Weekday() {
+			return true
+		}
+	}
+	for _, language := range compiledLanguages {
+		if _, ok := language.weekdays[word]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// normaliseDigits replaces the Arabic-Indic, Persian and full-width digits by ASCII ones
+func normaliseDigits(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '٠' && r <= '٩':
+			return '0' + (r - '٠')
+		case r >= '۰' && r <= '۹':
+			return '0' + (r - '۰')
+		case r >= '０' && r <= '９':
+			return '0' + (r - '０')
+		}
+		return r
+	}, text)
+}
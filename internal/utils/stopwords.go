@@ -92,6 +92,11 @@ func (stop *StopWords) stopWordsCount(lang string, text string) wordStats {
 	return ws
 }
 
+// StopWordsCount returns the number of distinct stop words of the given language found in the text
+func (stop StopWords) StopWordsCount(lang string, text string) int {
+	return stop.stopWordsCount(lang, text).stopWordCount
+}
+
 // SimpleLanguageDetector returns the language code for the text, based on its stop words
 func (stop StopWords) SimpleLanguageDetector(text string) string {
 	max := 0
@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultTrackingParams are the query parameters removed by NormalizeURL by default.
+// A trailing * matches any parameter with the prefix
+var DefaultTrackingParams = []string{
+	"utm_*", "fbclid", "gclid", "gclsrc", "dclid", "msclkid", "yclid", "twclid", "igshid",
+	"mc_cid", "mc_eid", "_ga", "_gl", "_hsenc", "_hsmi", "mkt_tok", "vero_id", "oly_anon_id",
+	"oly_enc_id", "wickedid", "ref_src", "cmpid", "ncid", "sr_share", "spm",
+}
+
+// defaultPorts are the ports that are dropped from the URLs of each scheme
+var defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+// ampCacheSuffix is the suffix of the hosts of the Google AMP cache, e.g.
+// https://www-example-com.cdn.ampproject.org/c/s/www.example.com/article
+const ampCacheSuffix = ".cdn.ampproject.org"
+
+// NormalizeURL returns the normalised form of a URL, resolved against base when it
+// is relative (base can be ""), so that the variants of a page get the same URL:
+//   - the scheme and the host are lowercased, the default port and the fragment are removed
+//   - the tracking parameters (see DefaultTrackingParams) are removed and the others sorted
+//   - the AMP variants (Google AMP cache, /amp suffix, amp=1) and the mobile hosts (m.example.com)
+//     are unwrapped to the regular page
+func NormalizeURL(rawURL string, base string, trackingParams []string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse "+rawURL)
+	}
+	if base != "" && !u.IsAbs() {
+		b, err := url.Parse(strings.TrimSpace(base))
+		if err != nil {
+			return "", errors.Wrap(err, "could not parse "+base)
+		}
+		u = b.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", errors.Errorf("not a web URL: %s", rawURL)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment, u.RawFragment = "", ""
+	u.User = nil
+	if unwrapped, ok := unwrapAMPCache(u); ok {
+		u = unwrapped
+	}
+	host, port := u.Hostname(), u.Port()
+	host = strings.TrimSuffix(host, ".")
+	if port == defaultPorts[u.Scheme] {
+		port = ""
+	}
+	host = unwrapMobileHost(host)
+	u.Host = host
+	if port != "" {
+		u.Host = host + ":" + port
+	}
+
+	u.Path = unwrapAMPPath(u.Path)
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	u.RawPath = ""
+	u.RawQuery = normalizeQuery(u.Query(), trackingParams)
+	u.ForceQuery = false
+	return u.String(), nil
+}
+
+// HashURL returns a stable hash of a normalised URL, e.g. to use it as a key
+func HashURL(normalizedURL string) string {
+	sum := sha256.Sum256([]byte(normalizedURL))
+	return hex.EncodeToString(sum[:])
+}
+
+// unwrapAMPCache returns the URL of the page served from the Google AMP cache
+func unwrapAMPCache(u *url.URL) (*url.URL, bool) {
+	if !strings.HasSuffix(u.Hostname(), ampCacheSuffix) {
+		return nil, false
+	}
+	// /c/s/www.example.com/path for https, /c/www.example.com/path for http,
+	// /v/... for the videos and /i/... for the images
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 3)
+	if len(parts) < 2 || (parts[0] != "c" && parts[0] != "v" && parts[0] != "i") {
+		return nil, false
+	}
+	scheme, rest := "http", strings.Join(parts[1:], "/")
+	if parts[1] == "s" {
+		if len(parts) < 3 {
+			return nil, false
+		}
+		scheme, rest = "https", parts[2]
+	}
+	unwrapped, err := url.Parse(scheme + "://" + rest)
+	if err != nil || unwrapped.Host == "" {
+		return nil, false
+	}
+	unwrapped.RawQuery = u.RawQuery
+	return unwrapped, true
+}
+
+// unwrapMobileHost removes the "m", "mobile" and "amp" labels of a host,
+// e.g. m.example.com or en.m.wikipedia.org
+func unwrapMobileHost(host string) string {
+	labels := strings.Split(host, ".")
+	if len(labels) <= 2 {
+		return host
+	}
+	kept := labels[:0]
+	for i, label := range labels {
+		if i < len(labels)-2 && (label == "m" || label == "mobile" || label == "amp") {
+			continue
+		}
+		kept = append(kept, label)
+	}
+	return strings.Join(kept, ".")
+}
+
+// unwrapAMPPath removes the AMP suffix or prefix of a path, e.g. /article/amp/,
+// /article.amp, /article.amp.html or /amp/article
+func unwrapAMPPath(path string) string {
+	switch {
+	case strings.HasSuffix(path, "/amp/"):
+		return strings.TrimSuffix(path, "amp/")
+	case strings.HasSuffix(path, "/amp"):
+		return strings.TrimSuffix(path, "/amp")
+	case strings.HasSuffix(path, ".amp.html"):
+		return strings.TrimSuffix(path, ".amp.html") + ".html"
+	case strings.HasSuffix(path, ".amp"):
+		return strings.TrimSuffix(path, ".amp")
+	case strings.HasPrefix(path, "/amp/"):
+		return strings.TrimPrefix(path, "/amp")
+	}
+	return path
+}
+
+// normalizeQuery removes the tracking and the AMP parameters, and sorts the others
+func normalizeQuery(query url.Values, trackingParams []string) string {
+	for name, values := range query {
+		if isTrackingParam(name, trackingParams) {
+			query.Del(name)
+			continue
+		}
+		switch strings.ToLower(name) {
+		case "amp", "outputtype", "usqp":
+			if len(values) == 1 && (values[0] == "" || values[0] == "1" || strings.EqualFold(values[0], "amp") ||
+				strings.EqualFold(values[0], "true") || strings.HasPrefix(values[0], "mq331AQ")) {
+				query.Del(name)
+			}
+		}
+	}
+	// Encode sorts the parameters by name, keeping the order of the values of each
+	return query.Encode()
+}
+
+func isTrackingParam(name string, trackingParams []string) bool {
+	name = strings.ToLower(name)
+	for _, param := range trackingParams {
+		param = strings.ToLower(param)
+		if strings.HasSuffix(param, "*") {
+			if strings.HasPrefix(name, strings.TrimSuffix(param, "*")) {
+				return true
+			}
+		} else if name == param {
+			return true
+		}
+	}
+	return false
+}
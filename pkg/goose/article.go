@@ -1,40 +1,130 @@
 package goose
 
 import (
-	"time"
-
-	"github.com/PuerkitoBio/goquery"
-	"github.com/fatih/set"
+	"github.com/advancedlogic/GoOse/internal/types"
 )
 
 // Article is a collection of properties extracted from the HTML body
-type Article struct {
-	Title           string             `json:"title,omitempty"`
-	TitleUnmodified string             `json:"titleunmodified,omitempty"`
-	CleanedText     string             `json:"content,omitempty"`
-	MetaDescription string             `json:"description,omitempty"`
-	MetaLang        string             `json:"lang,omitempty"`
-	MetaFavicon     string             `json:"favicon,omitempty"`
-	MetaKeywords    string             `json:"keywords,omitempty"`
-	CanonicalLink   string             `json:"canonicalurl,omitempty"`
-	Domain          string             `json:"domain,omitempty"`
-	TopNode         *goquery.Selection `json:"-"`
-	TopImage        string             `json:"image,omitempty"`
-	Tags            *set.Set           `json:"tags,omitempty"`
-	Movies          *set.Set           `json:"movies,omitempty"`
-	FinalURL        string             `json:"url,omitempty"`
-	LinkHash        string             `json:"linkhash,omitempty"`
-	RawHTML         string             `json:"rawhtml,omitempty"`
-	Doc             *goquery.Document  `json:"-"`
-	Links           []string           `json:"links,omitempty"`
-	PublishDate     *time.Time         `json:"publishdate,omitempty"`
-	AdditionalData  map[string]string  `json:"additionaldata,omitempty"`
-	Delta           int64              `json:"delta,omitempty"`
+type Article = types.Article
+
+// FetchInfo describes how the article was retrieved: redirect chain, final URL and HTTP metadata
+type FetchInfo = types.FetchInfo
+
+// Metadata is the schema.org metadata describing the article, e.g. the NewsArticle object of its JSON-LD
+type Metadata = types.Metadata
+
+// Formats of the structured metadata, by order of precedence
+const (
+	MetadataSourceJSONLD       = types.MetadataSourceJSONLD
+	MetadataSourceMicrodata    = types.MetadataSourceMicrodata
+	MetadataSourceRDFa         = types.MetadataSourceRDFa
+	MetadataSourceMicroformats = types.MetadataSourceMicroformats
+)
+
+// Entity is a person or an organization, e.g. an author or a publisher
+type Entity = types.Entity
+
+// Citation is the bibliographic metadata of a scholarly article
+type Citation = types.Citation
+
+// Breadcrumb is a link of the breadcrumb trail of a page
+type Breadcrumb = types.Breadcrumb
+
+// Icon is an icon of the site, declared by a <link> tag of the page or by its web app manifest
+type Icon = types.Icon
+
+// IconSize is a declared size of an icon, in pixels
+type IconSize = types.IconSize
+
+// Where the icons of the site are declared
+const (
+	IconRelIcon           = types.IconRelIcon
+	IconRelAppleTouchIcon = types.IconRelAppleTouchIcon
+	IconRelMaskIcon       = types.IconRelMaskIcon
+	IconRelManifest       = types.IconRelManifest
+)
+
+// BestIcon returns the icon that renders best at size×size pixels, or nil when there is none
+func BestIcon(icons []Icon, size int) *Icon {
+	return types.BestIcon(icons, size)
+}
+
+// Block is a block of the main content of the article, such as a heading or a paragraph
+type Block = types.Block
+
+// Link is a link of the text of a block
+type Link = types.Link
+
+// Types of the content blocks
+const (
+	BlockHeading   = types.BlockHeading
+	BlockParagraph = types.BlockParagraph
+	BlockList      = types.BlockList
+	BlockListItem  = types.BlockListItem
+	BlockQuote     = types.BlockQuote
+	BlockCode      = types.BlockCode
+	BlockTable     = types.BlockTable
+	BlockFigure    = types.BlockFigure
+	BlockEmbed     = types.BlockEmbed
+)
+
+// Style is a styled run of the text of a block
+type Style = types.Style
+
+// Styles of the runs of text
+const (
+	StyleStrong   = types.StyleStrong
+	StyleEmphasis = types.StyleEmphasis
+	StyleCode     = types.StyleCode
+)
+
+// BlocksText renders the blocks as plain text
+func BlocksText(blocks []Block) string {
+	return types.BlocksText(blocks)
+}
+
+// BlocksCleanedText renders the headings, the paragraphs, the lists and the quotes of the blocks
+// as plain text, as in Article.CleanedText
+func BlocksCleanedText(blocks []Block) string {
+	return types.BlocksCleanedText(blocks)
 }
 
-// ToString is a simple method to just show the title
-// TODO: add more fields and pretty print
-func (article *Article) ToString() string {
-	out := article.Title
-	return out
+// BlocksMarkdown renders the blocks as Markdown, as in Article.Markdown
+func BlocksMarkdown(blocks []Block) string {
+	return types.BlocksMarkdown(blocks)
 }
+
+// OpenGraph holds the Open Graph properties of the page (https://ogp.me)
+type OpenGraph = types.OpenGraph
+
+// OpenGraphImage is an og:image with its structured properties
+type OpenGraphImage = types.OpenGraphImage
+
+// TwitterCard holds the Twitter Card properties of the page
+type TwitterCard = types.TwitterCard
+
+// Where the charset of the article was found, by order of precedence
+const (
+	CharsetSourceBOM   = types.CharsetSourceBOM
+	CharsetSourceHTTP  = types.CharsetSourceHTTP
+	CharsetSourceMeta  = types.CharsetSourceMeta
+	CharsetSourceGuess = types.CharsetSourceGuess
+)
+
+// DateSource tells where a date of the article was found, and how much it can be trusted
+type DateSource = types.DateSource
+
+// Where the dates of the article were found, by order of precedence
+const (
+	DateSourceCitation     = types.DateSourceCitation
+	DateSourceJSONLD       = types.DateSourceJSONLD
+	DateSourceOpenGraph    = types.DateSourceOpenGraph
+	DateSourceMicrodata    = types.DateSourceMicrodata
+	DateSourceRDFa         = types.DateSourceRDFa
+	DateSourceMicroformats = types.DateSourceMicroformats
+	DateSourceMeta         = types.DateSourceMeta
+	DateSourcePubdate      = types.DateSourcePubdate
+	DateSourceTime         = types.DateSourceTime
+	DateSourceURL          = types.DateSourceURL
+	DateSourceText         = types.DateSourceText
+)
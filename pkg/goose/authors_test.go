@@ -0,0 +1,56 @@
+package goose
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Authors(t *testing.T) {
+	body := strings.Repeat("The council approved the new budget for the city parks after a long debate on Tuesday evening. ", 6)
+	page := `<html><head><title>Parks budget approved</title>
+<meta name="author" content="JANE DOE">
+<link rel="author" href="/authors/jane-doe" title="Jane Doe">
+</head><body>
+<h1>Parks budget approved</h1>
+<div class="story">
+<p>By <a href="/authors/jane-doe">Jane Doe</a> and John Smith | Reuters</p>
+<p>` + body + `</p>
+<p>By the way, the playgrounds will open in June and the council will publish the full plans later this year.</p>
+<p>` + body + `</p>
+<span itemprop="author" itemscope><span itemprop="name">Ana de la Cruz</span><a itemprop="url" href="https://example.com/ana">profile</a></span>
+</div></body></html>`
+
+	article, err := New().ExtractFromRawHTML(page, "https://example.com/news/parks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Entity{
+		{Name: "Ana de la Cruz", URL: "https://example.com/ana"},
+		{Name: "Jane Doe", URL: "https://example.com/authors/jane-doe"},
+		{Name: "John Smith"},
+	}
+	if len(article.Authors) != len(expected) {
+		t.Fatalf("expected %d authors, got %+v", len(expected), article.Authors)
+	}
+	for i, author := range expected {
+		if article.Authors[i] != author {
+			t.Errorf("expected author %+v, got %+v", author, article.Authors[i])
+		}
+	}
+	if strings.Contains(article.CleanedText, "Jane Doe") {
+		t.Errorf("expected the byline to be stripped from the text, got %q", article.CleanedText)
+	}
+	if !strings.Contains(article.CleanedText, "By the way") {
+		t.Errorf("expected the text starting with By to be kept, got %q", article.CleanedText)
+	}
+
+	// the JSON-LD authors come first
+	page = strings.Replace(page, "</head>", `<script type="application/ld+json">{"@type": "NewsArticle", "headline": "Parks",
+"author": {"@type": "Person", "name": "John Smith", "url": "https://example.com/john"}}</script></head>`, 1)
+	if article, err = New().ExtractFromRawHTML(page, "https://example.com/news/parks"); err != nil {
+		t.Fatal(err)
+	}
+	if len(article.Authors) != 3 || article.Authors[0] != (Entity{Name: "John Smith", URL: "https://example.com/john"}) {
+		t.Errorf("expected the JSON-LD author first, got %+v", article.Authors)
+	}
+}
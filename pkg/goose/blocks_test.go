@@ -0,0 +1,119 @@
+package goose
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const blocksPage = `<html><head><title>Parks budget approved</title></head><body><article>
+<h2>A long debate</h2>
+<p>The council approved the new budget for the <a href="/tags/parks">city parks</a> after a long debate on Tuesday evening.</p>
+<p>The plan, which was <em>first presented</em> in March, funds three new playgrounds and the repair of the old fountains.</p>
+<ul>
+<li>Three new playgrounds in the <strong>northern districts</strong> of the city</li>
+<li>The repair of the fountains, <a href="https://example.org/fountains">listed as heritage</a> since 1990
+<ol><li>The fountain of the main square and the one of the station</li></ol></li>
+</ul>
+<blockquote><p>We have waited for this budget for a very long time now.</p><p>It is good news for the families of the city.</p></blockquote>
+<pre><code class="language-go">total := parks + fountains
+fmt.Println(total)</code></pre>
+<table><caption>Budget by item</caption><thead><tr><th>Item</th><th>Amount</th></tr></thead>
+<tbody><tr><td>Playgrounds</td><td>1.2M</td></tr><tr><td>Fountains</td><td>0.8M</td></tr></tbody></table>
+<figure><img src="/images/park.jpg" alt="The park"><figcaption>The park of the city, seen from the northern gate of the old walls</figcaption></figure>
+<iframe src="https://www.youtube.com/embed/abc123"></iframe>
+<p>The works will start in the spring and should be over before the end of next year, the mayor said.</p>
+</article></body></html>`
+
+func Test_Blocks(t *testing.T) {
+	article, err := New().ExtractFromRawHTML(blocksPage, "https://example.com/news/parks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Block{
+		{Type: BlockHeading, Level: 2, Text: "A long debate"},
+		{Type: BlockParagraph, Text: "The council approved the new budget for the city parks after a long debate on Tuesday evening.",
+			Links: []Link{{Text: "city parks", Offset: 44, URL: "https://example.com/tags/parks"}}},
+		{Type: BlockParagraph, Text: "The plan, which was first presented in March, funds three new playgrounds and the repair of the old fountains.",
+			Styles: []Style{{Type: StyleEmphasis, Text: "first presented", Offset: 20}}},
+		{Type: BlockList, Items: []Block{
+			{Type: BlockListItem, Text: "Three new playgrounds in the northern districts of the city",
+				Styles: []Style{{Type: StyleStrong, Text: "northern districts", Offset: 29}}},
+			{Type: BlockListItem, Text: "The repair of the fountains, listed as heritage since 1990",
+				Links: []Link{{Text: "listed as heritage", Offset: 29, URL: "https://example.org/fountains"}}},
+			{Type: BlockListItem, Level: 1, Ordered: true, Text: "The fountain of the main square and the one of the station"},
+		}},
+		{Type: BlockQuote, Text: "We have waited for this budget for a very long time now.\nIt is good news for the families of the city."},
+		{Type: BlockCode, Language: "go", Text: "total := parks + fountains\nfmt.Println(total)"},
+		{Type: BlockTable, Text: "Budget by item", Header: true, Rows: [][]string{{"Item", "Amount"}, {"Playgrounds", "1.2M"}, {"Fountains", "0.8M"}}},
+		{Type: BlockFigure, Text: "The park of the city, seen from the northern gate of the old walls",
+			URL: "https://example.com/images/park.jpg", Alt: "The park"},
+		{Type: BlockEmbed, URL: "https://www.youtube.com/embed/abc123"},
+		{Type: BlockParagraph, Text: "The works will start in the spring and should be over before the end of next year, the mayor said."},
+	}
+	if !reflect.DeepEqual(article.Blocks, expected) {
+		t.Errorf("expected the blocks\n%+v\ngot\n%+v", expected, article.Blocks)
+	}
+	// the cleaned text leaves the code, the tables and the media out
+	if text := BlocksCleanedText(article.Blocks); article.CleanedText != text {
+		t.Errorf("expected the cleaned text to be rendered from the blocks, %q, got %q", text, article.CleanedText)
+	}
+	for _, block := range article.Blocks {
+		for _, link := range block.Links {
+			if block.Text[link.Offset:link.Offset+len(link.Text)] != link.Text {
+				t.Errorf("link %+v is not found at its offset in %q", link, block.Text)
+			}
+		}
+		for _, style := range block.Styles {
+			if block.Text[style.Offset:style.Offset+len(style.Text)] != style.Text {
+				t.Errorf("style %+v is not found at its offset in %q", style, block.Text)
+			}
+		}
+	}
+}
+
+func Test_BlocksText(t *testing.T) {
+	blocks := []Block{
+		{Type: BlockHeading, Level: 2, Text: "Budget"},
+		{Type: BlockList, Items: []Block{{Type: BlockListItem, Text: "Playgrounds"}, {Type: BlockListItem, Level: 1, Text: "North"}}},
+		{Type: BlockTable, Rows: [][]string{{"Item", "Amount"}, {"Fountains", "0.8M"}}},
+		{Type: BlockFigure, URL: "https://example.com/park.jpg"},
+		{Type: BlockParagraph, Text: "The works will start in the spring."},
+	}
+	expected := "Budget\n\nPlaygrounds\n\nNorth\n\nItem\tAmount\nFountains\t0.8M\n\nThe works will start in the spring."
+	if text := BlocksText(blocks); text != expected {
+		t.Errorf("expected %q, got %q", expected, text)
+	}
+	expected = "Budget\n\nPlaygrounds\n\nNorth\n\nThe works will start in the spring."
+	if text := BlocksCleanedText(blocks); text != expected {
+		t.Errorf("expected %q, got %q", expected, text)
+	}
+}
+
+func Test_BlocksByline(t *testing.T) {
+	page := `<html><head><title>Parks</title><meta name="author" content="Jane Doe"><meta name="author" content="John Roe"></head><body><article>
+<p>By Jane Doe and John Roe</p>` + markupParagraph + markupParagraph + `</article></body></html>`
+	article, err := New().ExtractFromRawHTML(page, "https://example.com/parks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, block := range article.Blocks {
+		if strings.Contains(block.Text, "Jane Doe") {
+			t.Errorf("expected the byline to be stripped from the blocks, got %+v", article.Blocks)
+		}
+	}
+}
+
+func Test_BlocksEmphasis(t *testing.T) {
+	page := `<html><head><title>Parks</title></head><body><article>
+<p>The plan was <em>first <a href="/news/march">presented</a> in March</em>, and it funds three new playgrounds.</p>` +
+		markupParagraph + `</article></body></html>`
+	article, err := New().ExtractFromRawHTML(page, "https://example.com/parks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Style{{Type: StyleEmphasis, Text: "first presented in March", Offset: 13}}
+	if len(article.Blocks) == 0 || !reflect.DeepEqual(article.Blocks[0].Styles, expected) {
+		t.Errorf("expected the styles %+v, got the blocks %+v", expected, article.Blocks)
+	}
+}
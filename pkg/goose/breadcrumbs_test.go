@@ -0,0 +1,90 @@
+package goose
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func Test_Breadcrumbs(t *testing.T) {
+	paragraph := "<p>The council approved the new budget for the city parks after a long debate.</p>"
+	trail := []Breadcrumb{
+		{Name: "Home", URL: "https://example.com/"},
+		{Name: "News", URL: "https://example.com/news/"},
+		{Name: "Local", URL: "https://example.com/news/local/"},
+	}
+	tests := []struct {
+		name        string
+		head        string
+		body        string
+		breadcrumbs []Breadcrumb
+		section     string
+	}{
+		{
+			name: "JSON-LD",
+			head: `<script type="application/ld+json">{"@context": "https://schema.org", "@type": "BreadcrumbList", "itemListElement": [
+{"@type": "ListItem", "position": 3, "name": "Local", "item": "https://example.com/news/local/"},
+{"@type": "ListItem", "position": 1, "name": "Home", "item": "https://example.com/"},
+{"@type": "ListItem", "position": 2, "item": {"@id": "/news/", "name": "News"}}]}</script>`,
+			breadcrumbs: trail,
+			section:     "Local",
+		},
+		{
+			name: "microdata",
+			body: `<ol itemscope itemtype="https://schema.org/BreadcrumbList">
+<li itemprop="itemListElement" itemscope itemtype="https://schema.org/ListItem"><a itemprop="item" href="/"><span itemprop="name">Home</span></a><meta itemprop="position" content="1"></li>
+<li itemprop="itemListElement" itemscope itemtype="https://schema.org/ListItem"><a itemprop="item" href="/news/"><span itemprop="name">News</span></a><meta itemprop="position" content="2"></li>
+<li itemprop="itemListElement" itemscope itemtype="https://schema.org/ListItem"><a itemprop="item" href="/news/local/"><span itemprop="name">Local</span></a><meta itemprop="position" content="3"></li>
+</ol>`,
+			breadcrumbs: trail,
+			section:     "Local",
+		},
+		{
+			name: "nav",
+			body: `<nav aria-label="Breadcrumb"><ol><li><a href="/">Home</a> ›</li><li><a href="/news/">News</a> ›</li>
+<li><a href="/news/local/">Local</a> ›</li><li aria-current="page">Parks budget approved</li></ol></nav>`,
+			breadcrumbs: append(append([]Breadcrumb{}, trail...), Breadcrumb{Name: "Parks budget approved"}),
+			section:     "Local",
+		},
+		{
+			name:        "article:section first",
+			head:        `<meta property="article:section" content="Politics">`,
+			body:        `<div class="breadcrumbs"><a href="/">Home</a> &gt; <a href="/news/">News</a></div>`,
+			breadcrumbs: trail[:2],
+			section:     "Politics",
+		},
+		{
+			name:    "JSON-LD articleSection",
+			head:    `<script type="application/ld+json">{"@type": "NewsArticle", "headline": "Parks budget approved", "articleSection": ["City Hall", "Local"]}</script>`,
+			section: "City Hall",
+		},
+	}
+	for _, test := range tests {
+		page := "<html><head><title>Parks budget approved</title>" + test.head + "</head><body>" + test.body + paragraph + "</body></html>"
+		article, err := New().ExtractFromRawHTML(page, "https://example.com/news/local/parks")
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(article.Breadcrumbs, test.breadcrumbs) {
+			t.Errorf("%s: expected the breadcrumbs %+v, got %+v", test.name, test.breadcrumbs, article.Breadcrumbs)
+		}
+		if article.Section != test.section {
+			t.Errorf("%s: expected the section %q, got %q", test.name, test.section, article.Section)
+		}
+	}
+}
+
+func Test_BreadcrumbsFixture(t *testing.T) {
+	raw, err := ioutil.ReadFile("../../sites/wsj.com.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	article, err := New().ExtractFromRawHTML(string(raw), "http://www.wsj.com/articles/big-obama-donors-stay-on-sidelines-in-2016-race-1447375042")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Breadcrumb{{Name: "Politics", URL: "http://www.wsj.com/news/politics"}}
+	if !reflect.DeepEqual(article.Breadcrumbs, expected) || article.Section != "Politics" {
+		t.Errorf("unexpected breadcrumbs %+v and section %q", article.Breadcrumbs, article.Section)
+	}
+}
@@ -0,0 +1,91 @@
+package goose
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_ResponseCache(t *testing.T) {
+	requests := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/etag":
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/modified":
+			w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+			if r.Header.Get("If-Modified-Since") == "Wed, 21 Oct 2015 07:28:00 GMT" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/fresh":
+			w.Header().Set("Cache-Control", "max-age=60")
+		case "/nostore":
+			w.Header().Set("Cache-Control", "no-store")
+			w.Header().Set("ETag", `"v1"`)
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><head><title>cached</title></head></html>"))
+	}))
+	defer ts.Close()
+
+	config := GetDefaultConfiguration()
+	config.LocalStoragePath = t.TempDir()
+
+	expectations := []struct {
+		path     string
+		statuses []string
+		requests int
+	}{
+		{"/etag", []string{CacheMiss, CacheRevalidated}, 2},
+		{"/modified", []string{CacheMiss, CacheRevalidated}, 2},
+		{"/fresh", []string{CacheMiss, CacheHit}, 1},
+		{"/nostore", []string{CacheMiss, CacheMiss}, 2},
+	}
+	for _, expected := range expectations {
+		g := NewWithConfig(config)
+		for i, status := range expected.statuses {
+			article, err := g.ExtractFromURL(ts.URL + expected.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if article.Fetch.CacheStatus != status || article.Title != "cached" {
+				t.Errorf("%s #%d: expected %q, got %q (title %q)", expected.path, i, status, article.Fetch.CacheStatus, article.Title)
+			}
+		}
+		if requests[expected.path] != expected.requests {
+			t.Errorf("%s: expected %d requests, got %d", expected.path, expected.requests, requests[expected.path])
+		}
+	}
+
+	// the responses outlive the instance in the on-disk store
+	article, err := NewWithConfig(config).ExtractFromURL(ts.URL + "/fresh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Fetch.CacheStatus != CacheHit || requests["/fresh"] != 1 {
+		t.Errorf("expected a hit from the disk, got %q after %d requests", article.Fetch.CacheStatus, requests["/fresh"])
+	}
+}
+
+func Test_MemoryCacheEviction(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", &CachedResponse{Body: []byte("a")})
+	cache.Set("b", &CachedResponse{Body: []byte("b")})
+	cache.Get("a")
+	cache.Set("c", &CachedResponse{Body: []byte("c")})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if response, ok := cache.Get(key); !ok || string(response.Body) != key {
+			t.Errorf("expected %q to be cached", key)
+		}
+	}
+}
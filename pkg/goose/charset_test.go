@@ -1,8 +1,17 @@
 package goose
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
 	"testing"
+
 	"github.com/advancedlogic/GoOse/internal/utils"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
 )
 
 func TestNormaliseCharset(t *testing.T) {
@@ -83,3 +92,122 @@ func TestNormaliseCharset(t *testing.T) {
 		t.Errorf("Was expecting '%s', got '%s'", expected, actual)
 	}
 }
+
+func Test_CharsetPrecedence(t *testing.T) {
+	title := "Новости дня"
+	encoded, err := charmap.KOI8R.NewEncoder().String("<html><head><meta charset=\"utf-8\"><title>" + title + "</title></head></html>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=KOI8-R")
+		w.Write([]byte(encoded))
+	}))
+	defer ts.Close()
+
+	// the HTTP header wins over a wrong meta tag
+	article, err := New().ExtractFromURL(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Charset != "KOI8-R" || article.CharsetSource != CharsetSourceHTTP {
+		t.Errorf("expected KOI8-R from http, got %q from %q", article.Charset, article.CharsetSource)
+	}
+	if article.Title != title {
+		t.Errorf("expected title %q, got %q", title, article.Title)
+	}
+
+	// the byte order mark wins over everything else
+	article, err = New().ExtractFromRawHTML("\xEF\xBB\xBF<html><head><meta charset=\"iso-8859-1\"><title>"+title+"</title></head></html>", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Charset != "UTF-8" || article.CharsetSource != CharsetSourceBOM {
+		t.Errorf("expected UTF-8 from bom, got %q from %q", article.Charset, article.CharsetSource)
+	}
+	if article.Title != title {
+		t.Errorf("expected title %q, got %q", title, article.Title)
+	}
+
+	// then the meta tags
+	article, err = New().ExtractFromRawHTML(ReadRawHTML(Article{Domain: "charset_euc_jp"}), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Charset != "EUC-JP" || article.CharsetSource != CharsetSourceMeta {
+		t.Errorf("expected EUC-JP from meta, got %q from %q", article.Charset, article.CharsetSource)
+	}
+
+	// and finally a guess
+	article, err = New().ExtractFromRawHTML("<html><head><title>"+title+"</title></head></html>", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Charset != "UTF-8" || article.CharsetSource != CharsetSourceGuess {
+		t.Errorf("expected UTF-8 from a guess, got %q from %q", article.Charset, article.CharsetSource)
+	}
+}
+
+var metaCharsetRegexp = regexp.MustCompile(`(?i)<meta[^>]*charset[^>]*>`)
+
+func Test_DetectCharset(t *testing.T) {
+	// the fixtures with their declarations stripped
+	fixtures := map[string]string{
+		"charset_euc_jp":     "EUC-JP",
+		"charset_koi8_r":     "KOI8-R",
+		"charset_iso_8859_1": "CP1252",
+		"charset_euc_kr":     "UTF-8", // the fixture is actually UTF-8
+		"charset_shift_jis":  "UTF-8", // ditto
+	}
+	for domain, expected := range fixtures {
+		raw := metaCharsetRegexp.ReplaceAllString(ReadRawHTML(Article{Domain: domain}), "")
+		if actual, _ := utils.DetectCharset(raw); actual != expected {
+			t.Errorf("%s: was expecting %q, got %q", domain, expected, actual)
+		}
+	}
+
+	samples := []struct {
+		charset  string
+		encoding encoding.Encoding
+		text     string
+	}{
+		{"SHIFT_JIS", japanese.ShiftJIS, "日本語の文章をシフトJISで保存すると、ブラウザはその規則に従って読み取ります。宣言がない場合は文字化けが発生してしまいます。"},
+		{"GB18030", simplifiedchinese.GB18030, "我们在这个国家的时候，他说这是一个很大的问题。人们都知道中国的经济发展得很快，但是还有很多事情要做。"},
+		{"BIG5", traditionalchinese.Big5, "我們在這個國家的時候，他說這是一個很大的問題。人們都知道中國的經濟發展得很快，但是還有很多事情要做。"},
+		{"CP1251", charmap.Windows1251, "Этот перевод может быть устаревшим. Смотрите английскую версию для ознакомления со всеми последними изменениями в документе."},
+		{"CP1252", charmap.Windows1252, "Der Apache wird konfiguriert, indem Direktiven in einfache Textdateien eingetragen werden. Die Hauptkonfigurationsdatei heißt üblicherweise httpd.conf."},
+	}
+	for _, sample := range samples {
+		encoded, err := sample.encoding.NewEncoder().String("<html><body><p>" + sample.text + "</p></body></html>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual, _ := utils.DetectCharset(encoded); actual != sample.charset {
+			t.Errorf("was expecting %q, got %q", sample.charset, actual)
+		}
+	}
+}
+
+func Test_WrongCharsetDeclaration(t *testing.T) {
+	// the fixture declares EUC-KR but is encoded in UTF-8
+	article, err := New().ExtractFromRawHTML(ReadRawHTML(Article{Domain: "charset_euc_kr"}), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Charset != "UTF-8" || article.CharsetSource != CharsetSourceGuess {
+		t.Errorf("expected UTF-8 from a guess, got %q from %q", article.Charset, article.CharsetSource)
+	}
+
+	title := "Новости дня"
+	encoded, err := charmap.KOI8R.NewEncoder().String("<html><head><meta charset=\"windows-1251\"><title>" + title + "</title></head><body><p>Этот перевод может быть устаревшим. Смотрите английскую версию для ознакомления со всеми последними изменениями.</p></body></html>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	article, err = New().ExtractFromRawHTML(encoded, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Charset != "KOI8-R" || article.Title != title {
+		t.Errorf("expected %q decoded from KOI8-R, got %q from %q", title, article.Title, article.Charset)
+	}
+}
@@ -0,0 +1,87 @@
+package goose
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_Citation(t *testing.T) {
+	page := `<html><head><title>Sleep and memory | Journal of Examples</title>
+<meta name="citation_title" content="Sleep spindles and the consolidation of memory">
+<meta name="citation_author" content="Doe, Jane">
+<meta name="citation_author" content="Roe, John">
+<meta name="citation_doi" content="doi:10.1234/jex.2023.0042">
+<meta name="citation_journal_title" content="Journal of Examples">
+<meta name="citation_publisher" content="Example Press">
+<meta name="citation_issn" content="1234-5678">
+<meta name="citation_volume" content="12">
+<meta name="citation_issue" content="3">
+<meta name="citation_firstpage" content="101">
+<meta name="citation_lastpage" content="118">
+<meta name="citation_publication_date" content="2023/04/15">
+<meta name="citation_pdf_url" content="/content/12/3/101.full.pdf">
+<meta name="dc.date" content="2023-05-01">
+</head><body><article><p>Sleep spindles have long been associated with the consolidation of declarative memories.</p></article></body></html>`
+	article, err := New().ExtractFromRawHTML(page, "https://journal.example.com/content/12/3/101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	published := time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC)
+	expected := &Citation{
+		Title:           "Sleep spindles and the consolidation of memory",
+		Authors:         []string{"Doe, Jane", "Roe, John"},
+		DOI:             "10.1234/jex.2023.0042",
+		Journal:         "Journal of Examples",
+		Publisher:       "Example Press",
+		ISSN:            "1234-5678",
+		Volume:          "12",
+		Issue:           "3",
+		FirstPage:       "101",
+		LastPage:        "118",
+		PublicationDate: &published,
+		PDFURL:          "https://journal.example.com/content/12/3/101.full.pdf",
+	}
+	if article.Citation == nil || article.Citation.PublicationDate == nil || !article.Citation.PublicationDate.Equal(published) {
+		t.Fatalf("unexpected citation %+v", article.Citation)
+	}
+	citation := *article.Citation
+	citation.PublicationDate = &published
+	if !reflect.DeepEqual(&citation, expected) {
+		t.Errorf("expected the citation\n%+v\ngot\n%+v", expected, citation)
+	}
+	if article.Title != expected.Title {
+		t.Errorf("expected the title of the citation, got %q", article.Title)
+	}
+	if article.PublishDate == nil || !article.PublishDate.Equal(published) || article.PublishDateSource.Name != DateSourceCitation {
+		t.Errorf("expected the publication date of the citation, got %v from %+v", article.PublishDate, article.PublishDateSource)
+	}
+}
+
+func Test_DublinCoreCitation(t *testing.T) {
+	paragraph := `<p>Sleep spindles have long been associated with the consolidation of declarative memories.</p>`
+	repository := `<html><head><title>Repository item</title>
+<meta name="DC.title" content="Sleep spindles and memory">
+<meta name="DC.creator" content="Doe, Jane">
+<meta name="DC.identifier" content="https://doi.org/10.1234%2Fjex.2023.0042">
+<meta name="DCTERMS.issued" content="2023">
+</head><body>` + paragraph + `</body></html>`
+	article, err := New().ExtractFromRawHTML(repository, "https://repository.example.com/items/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Citation == nil || article.Citation.DOI != "10.1234/jex.2023.0042" || article.Citation.Title != "Sleep spindles and memory" ||
+		!reflect.DeepEqual(article.Citation.Authors, []string{"Doe, Jane"}) || article.Citation.PublicationDate.Year() != 2023 {
+		t.Errorf("unexpected citation %+v", article.Citation)
+	}
+
+	news := `<html><head><title>Council approves the parks budget</title>
+<meta name="dc.title" content="Parks budget"><meta name="dc.identifier" content="urn:uuid:a5d38fff">
+<meta name="prism.section" content="local"></head><body>` + paragraph + `</body></html>`
+	if article, err = New().ExtractFromRawHTML(news, "https://example.com/parks"); err != nil {
+		t.Fatal(err)
+	}
+	if article.Citation != nil || article.Title != "Council approves the parks budget" {
+		t.Errorf("expected no citation for a news article, got %+v and the title %q", article.Citation, article.Title)
+	}
+}
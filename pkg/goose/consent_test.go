@@ -0,0 +1,113 @@
+package goose
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const consentPage = `<html><head><title>Before you continue</title></head><body>
+<div id="onetrust-consent-sdk"><div id="onetrust-banner-sdk"><p>We and our partners use cookies to store and
+access information on your device, to personalise ads and content, measure their performance and develop our
+products. You can accept all cookies, reject them or manage your preferences at any time from the privacy
+settings link at the bottom of every page of this website, including the ones about legitimate interest.</p>
+<button>Accept all</button></div></div></body></html>`
+
+var articleParagraph = strings.Repeat("The council approved the new budget for the city parks after a long debate on Tuesday evening. ", 8)
+
+func Test_ConsentWall(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wall":
+			w.Write([]byte(consentPage))
+		case "/banner":
+			// the article is there, under the banner
+			w.Write([]byte(strings.Replace(consentPage, "</body>", "<article><p>"+articleParagraph+"</p></article></body>", 1)))
+		case "/redirect":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "1"})
+			http.Redirect(w, r, "/consented", http.StatusFound)
+		case "/consented":
+			if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "1" {
+				if cookie, err = r.Cookie("euconsent"); err != nil || cookie.Value != "yes" {
+					w.Write([]byte(consentPage))
+					return
+				}
+			}
+			w.Write([]byte("<html><head><title>consented</title></head><body><article><p>" + articleParagraph + "</p></article></body></html>"))
+		}
+	}))
+	defer ts.Close()
+
+	g := New()
+	_, err := g.ExtractFromURL(ts.URL + "/wall")
+	var consentErr *ConsentRequiredError
+	if !errors.As(err, &consentErr) || consentErr.Provider != "OneTrust" || consentErr.URL != ts.URL+"/wall" {
+		t.Errorf("expected a ConsentRequiredError, got %v", err)
+	}
+
+	article, err := g.ExtractFromURL(ts.URL + "/banner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(article.CleanedText, "cookies") || !strings.Contains(article.CleanedText, "council") {
+		t.Errorf("expected the banner to be removed from the article, got %q", article.CleanedText)
+	}
+
+	// the cookies set before a redirect are sent to the redirected URL
+	if article, err = g.ExtractFromURL(ts.URL + "/redirect"); err != nil || article.Title != "consented" {
+		t.Errorf("expected the cookie to follow the redirect, got %v", err)
+	}
+
+	// the instance keeps the session cookie, unlike a new one
+	if _, err = New().ExtractFromURL(ts.URL + "/consented"); !errors.As(err, &consentErr) {
+		t.Errorf("expected a ConsentRequiredError without the consent cookie, got %v", err)
+	}
+	config := GetDefaultConfiguration()
+	config.ConsentCookies = map[string][]*http.Cookie{"127.0.0.1": {{Name: "euconsent", Value: "yes"}}}
+	if article, err = NewWithConfig(config).ExtractFromURL(ts.URL + "/consented"); err != nil || article.Title != "consented" {
+		t.Errorf("expected the preset consent cookie to be sent, got %v", err)
+	}
+}
+
+func Test_PersistentCookieJar(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "consent", Value: "given", MaxAge: 3600})
+		http.SetCookie(w, &http.Cookie{Name: "gone", Value: "soon", MaxAge: -1})
+		w.Write([]byte("<html><head><title>cookies</title></head></html>"))
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	jar, err := NewPersistentCookieJar(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := GetDefaultConfiguration()
+	config.CookieJar = jar
+	if _, err := NewWithConfig(config).ExtractFromURL(ts.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewPersistentCookieJar(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(ts.URL)
+	cookies := reloaded.Cookies(u)
+	if len(cookies) != 1 || cookies[0].Name != "consent" || cookies[0].Value != "given" {
+		t.Errorf("expected the consent cookie to be reloaded, got %v", cookies)
+	}
+}
+
+func Test_ConsentWallEmbed(t *testing.T) {
+	// a short article around a video whose URL mentions consent is not a consent interstitial
+	page := `<html><head><title>Parks</title></head><body><article><p>Watch the mayor present the new budget for the city parks.</p>
+<iframe src="https://www.youtube.com/embed/abc123?list=consent-explained"></iframe></article></body></html>`
+	if _, err := New().ExtractFromRawHTML(page, "https://example.com/parks"); err != nil {
+		t.Errorf("expected the page to be extracted, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package goose
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_DateSources(t *testing.T) {
+	paragraph := "<p>The council approved the new budget for the city parks on March 1, 2020 after a long debate.</p>"
+	tests := []struct {
+		name       string
+		head       string
+		body       string
+		url        string
+		published  string
+		source     string
+		confidence float64
+		modified   string
+	}{
+		{
+			name: "JSON-LD first",
+			head: `<script type="application/ld+json">{"@type": "NewsArticle", "datePublished": "2024-05-03T10:00:00Z", "dateModified": "2024-05-04T10:00:00Z"}</script>
+<meta property="article:published_time" content="2024-01-01T00:00:00Z">`,
+			published: "2024-05-03T10:00:00Z", source: DateSourceJSONLD, confidence: 0.95, modified: "2024-05-04T10:00:00Z",
+		},
+		{
+			name: "Open Graph",
+			head: `<meta property="article:published_time" content="2024-05-03T10:00:00+02:00">
+<meta property="article:modified_time" content="2024-05-04T10:00:00+02:00">`,
+			published: "2024-05-03T10:00:00+02:00", source: DateSourceOpenGraph, confidence: 0.9, modified: "2024-05-04T10:00:00+02:00",
+		},
+		{
+			name:      "microdata",
+			body:      `<span itemprop="datePublished" content="2024-05-03T10:00:00Z">3 May</span><span itemprop="dateModified">2024-05-04T10:00:00Z</span>`,
+			published: "2024-05-03T10:00:00Z", source: DateSourceMicrodata, confidence: 0.85, modified: "2024-05-04T10:00:00Z",
+		},
+		{
+			name:      "meta",
+			head:      `<meta name="pubdate" content="2024-05-03T10:00:00Z"><meta name="dcterms.modified" content="2024-05-04T10:00:00Z">`,
+			published: "2024-05-03T10:00:00Z", source: DateSourceMeta, confidence: 0.8, modified: "2024-05-04T10:00:00Z",
+		},
+		{
+			name:      "time pubdate",
+			body:      `<aside><time datetime="2019-01-01T00:00:00Z">old</time></aside><article><time pubdate datetime="2024-05-03T10:00:00Z">3 May</time></article>`,
+			published: "2024-05-03T10:00:00Z", source: DateSourcePubdate, confidence: 0.75,
+		},
+		{
+			name:      "time in the article",
+			body:      `<aside><time datetime="2019-01-01T00:00:00Z">old</time></aside><article><time datetime="2024-05-03T10:00:00Z">3 May</time></article>`,
+			published: "2024-05-03T10:00:00Z", source: DateSourceTime, confidence: 0.6,
+		},
+		{
+			name:      "URL path",
+			url:       "https://example.com/news/2024/05/03/parks-budget",
+			published: "2024-05-03T00:00:00Z", source: DateSourceURL, confidence: 0.5,
+		},
+		{
+			name:      "text",
+			url:       "https://example.com/news/2024/02/30/invalid",
+			published: "2020-03-01T00:00:00Z", source: DateSourceText, confidence: 0.3,
+		},
+	}
+	for _, test := range tests {
+		url := test.url
+		if url == "" {
+			url = "https://example.com/news/parks"
+		}
+		page := "<html><head><title>Parks</title>" + test.head + "</head><body>" + test.body + paragraph + "</body></html>"
+		article, err := New().ExtractFromRawHTML(page, url)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		published, _ := time.Parse(time.RFC3339, test.published)
+		if article.PublishDate == nil || !article.PublishDate.Equal(published) || article.PublishDate.Format(time.RFC3339) != test.published {
+			t.Errorf("%s: expected the publish date %s, got %v", test.name, test.published, article.PublishDate)
+		}
+		if article.PublishDateSource == nil || *article.PublishDateSource != (DateSource{Name: test.source, Confidence: test.confidence}) {
+			t.Errorf("%s: expected the source %s (%v), got %+v", test.name, test.source, test.confidence, article.PublishDateSource)
+		}
+		switch {
+		case test.modified == "" && (article.ModifiedDate != nil || article.ModifiedDateSource != nil):
+			t.Errorf("%s: expected no modified date, got %v", test.name, article.ModifiedDate)
+		case test.modified != "" && (article.ModifiedDate == nil || article.ModifiedDate.Format(time.RFC3339) != test.modified || article.ModifiedDateSource.Name != test.source):
+			t.Errorf("%s: expected the modified date %s, got %v", test.name, test.modified, article.ModifiedDate)
+		}
+	}
+}
+
+func Test_TextDates(t *testing.T) {
+	fetchTime := time.Date(2024, 5, 10, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		lang     string
+		text     string
+		expected string
+	}{
+		{"de", "Veröffentlicht am 3. März 2024 um 10:15 Uhr", "2024-03-03T10:15:00Z"},
+		{"es", "Publicado el 12 de mayo de 2023", "2023-05-12T00:00:00Z"},
+		{"fr", "Mis à jour le vendredi 1er mars 2024", "2024-03-01T00:00:00Z"},
+		{"zh", "发布时间：2024年5月3日 08:30", "2024-05-03T08:30:00Z"},
+		{"ru", "Опубликовано вчера в 18:20", "2024-05-09T18:20:00Z"},
+		{"ru", "3 мая 2024 г.", "2024-05-03T00:00:00Z"},
+		{"en", "Posted 2 hours ago by the newsroom", "2024-05-10T13:30:00Z"},
+		{"en", "Published Friday, May 3, 2024 at 9:05 pm GMT+2", "2024-05-03T21:05:00+02:00"},
+		{"es", "hace 3 días", "2024-05-07T15:30:00Z"},
+		{"nl", "5 uur geleden", "2024-05-10T10:30:00Z"},
+		{"id", "Kamis, 2 Mei 2024 14:00 WIB", "2024-05-02T14:00:00Z"},
+		{"ar", "نشر في ٣ مارس ٢٠٢٤", "2024-03-03T00:00:00Z"},
+		{"sr", "пре 2 дана", "2024-05-08T15:30:00Z"},
+	}
+	for _, test := range tests {
+		page := `<html lang="` + test.lang + `"><head><title>News</title></head><body><p class="byline">` + test.text + `</p></body></html>`
+		c := NewCrawler(GetDefaultConfiguration())
+		c.FetchTime = fetchTime
+		article, err := c.Crawl(page, "https://example.com/news")
+		if err != nil {
+			t.Fatalf("%s: %v", test.text, err)
+		}
+		if article.PublishDate == nil || article.PublishDate.Format(time.RFC3339) != test.expected {
+			t.Errorf("%s: expected %s, got %v", test.text, test.expected, article.PublishDate)
+		}
+	}
+}
+
+func Test_TextDatesRelative(t *testing.T) {
+	fetchTime := time.Date(2024, 5, 10, 15, 30, 0, 0, time.UTC)
+	paragraph := "<p>The council approved the new budget for the city parks after a long debate.</p>"
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"outside the byline", `<nav>Subscribe today and save</nav>` + paragraph, ""},
+		{"absolute first", `<p>An hour ago you missed this. Posted March 3, 2020</p>` + paragraph, "2020-03-03T00:00:00Z"},
+		{"absolute in the byline first", `<p class="byline">Updated 2 hours ago, first published March 3, 2020</p>` + paragraph, "2020-03-03T00:00:00Z"},
+		{"in a time element", `<time>Yesterday at 9:15</time>` + paragraph, "2024-05-09T09:15:00Z"},
+		{"in the words of another language", `<p class="byline">Heute, vor 2 Stunden</p>` + paragraph, ""},
+	}
+	for _, test := range tests {
+		page := `<html lang="en"><head><title>News</title></head><body>` + test.body + `</body></html>`
+		c := NewCrawler(GetDefaultConfiguration())
+		c.FetchTime = fetchTime
+		article, err := c.Crawl(page, "https://example.com/news")
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		switch {
+		case test.expected == "" && article.PublishDate != nil:
+			t.Errorf("%s: expected no publish date, got %v (%+v)", test.name, article.PublishDate, article.PublishDateSource)
+		case test.expected != "" && (article.PublishDate == nil || article.PublishDate.Format(time.RFC3339) != test.expected):
+			t.Errorf("%s: expected %s, got %v", test.name, test.expected, article.PublishDate)
+		}
+	}
+}
+
+func Test_TextDatePageTimeZone(t *testing.T) {
+	page := `<html><head><title>News</title><meta name="dcterms.modified" content="2024-05-04T10:00:00+02:00"></head>
+<body><p>Published on 3 May 2024, 18:00</p></body></html>`
+	article, err := New().ExtractFromRawHTML(page, "https://example.com/news")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.PublishDate == nil || article.PublishDate.Format(time.RFC3339) != "2024-05-03T18:00:00+02:00" {
+		t.Errorf("expected the publish date in the time zone of the page, got %v", article.PublishDate)
+	}
+}
@@ -0,0 +1,41 @@
+package goose
+
+import (
+	"github.com/advancedlogic/GoOse/internal/fetcher"
+	"github.com/advancedlogic/GoOse/internal/types"
+)
+
+// FetchError is returned when a page cannot be fetched, either because of a network
+// failure (StatusCode is 0) or because the server answered with an error status code
+type FetchError = fetcher.FetchError
+
+// ErrBodyTooLarge is returned when a response exceeds MaxBodySize or MaxDecompressedSize
+var ErrBodyTooLarge = fetcher.ErrBodyTooLarge
+
+// UnsupportedContentTypeError is returned for documents that are neither HTML nor handled by a ContentHandler
+type UnsupportedContentTypeError = fetcher.UnsupportedContentTypeError
+
+// RobotsDisallowedError is returned for the URLs that robots.txt forbids to the user agent
+type RobotsDisallowedError = fetcher.RobotsDisallowedError
+
+// DecodeError is returned when the document cannot be converted from its charset to UTF-8
+type DecodeError = types.DecodeError
+
+// ConsentRequiredError is returned when the page is a consent interstitial rather than the article.
+// Preset the consent cookies of the domain with Configuration.ConsentCookies to get past it
+type ConsentRequiredError = types.ConsentRequiredError
+
+// ExtractionError is returned when a phase of the extraction fails
+type ExtractionError = types.ExtractionError
+
+// ErrNoContent is returned, when Configuration.RequireContent is set, if no main content could be found
+var ErrNoContent = types.ErrNoContent
+
+// Extraction phases reported by ExtractionError
+const (
+	PhasePreprocess  = types.PhasePreprocess
+	PhasePublishDate = types.PhasePublishDate
+	PhaseClean       = types.PhaseClean
+	PhaseBestNode    = types.PhaseBestNode
+	PhaseFormat      = types.PhaseFormat
+)
@@ -0,0 +1,73 @@
+package goose
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_FetchError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	_, err := New().ExtractFromURL(ts.URL)
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("expected a FetchError, got %v", err)
+	}
+	if fetchErr.URL != ts.URL || fetchErr.StatusCode != http.StatusNotFound {
+		t.Errorf("unexpected fetch error %q %d", fetchErr.URL, fetchErr.StatusCode)
+	}
+}
+
+func Test_DecodeError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=x-unknown")
+		w.Write([]byte("<html><head><title>caf\xe9</title></head></html>"))
+	}))
+	defer ts.Close()
+
+	_, err := New().ExtractFromURL(ts.URL)
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || decodeErr.Charset != "X-UNKNOWN" {
+		t.Errorf("expected a DecodeError for X-UNKNOWN, got %v", err)
+	}
+	var extractionErr *ExtractionError
+	if !errors.As(err, &extractionErr) || extractionErr.Phase != PhasePreprocess || extractionErr.URL != ts.URL {
+		t.Errorf("expected an ExtractionError in the preprocess phase, got %v", err)
+	}
+
+	// unsupported charsets in meta tags are replaced by a guess
+	article, err := New().ExtractFromRawHTML("<html><head><meta charset=\"x-unknown\"><title>caf\xe9</title></head></html>", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Title != "café" || article.CharsetSource != CharsetSourceGuess {
+		t.Errorf("expected title %q from a guess, got %q from %q", "café", article.Title, article.CharsetSource)
+	}
+}
+
+func Test_ErrNoContent(t *testing.T) {
+	html := "<html><head><title>empty</title></head><body></body></html>"
+	article, err := New().ExtractFromRawHTML(html, "http://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Title != "empty" || article.TopNode != nil {
+		t.Errorf("expected the metadata only, got title %q", article.Title)
+	}
+
+	config := GetDefaultConfiguration()
+	config.RequireContent = true
+	_, err = NewWithConfig(config).ExtractFromRawHTML(html, "http://example.com/")
+	if !errors.Is(err, ErrNoContent) {
+		t.Errorf("expected ErrNoContent, got %v", err)
+	}
+	var extractionErr *ExtractionError
+	if !errors.As(err, &extractionErr) || extractionErr.Phase != PhaseBestNode {
+		t.Errorf("expected an ExtractionError in the best node phase, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package goose
+
+import (
+	"net/http"
+
+	"github.com/advancedlogic/GoOse/internal/fetcher"
+)
+
+// Fetcher retrieves the raw content of a web page
+type Fetcher = fetcher.Fetcher
+
+// FetchResult is the response returned by a Fetcher: body, status, headers and final URL
+type FetchResult = fetcher.FetchResult
+
+// HTTPFetcher is the default Fetcher, backed by a net/http client
+type HTTPFetcher = fetcher.HTTPFetcher
+
+// NewHTTPFetcher returns a Fetcher using the given client (a zero http.Client when nil).
+// Proxies, TLS settings or instrumentation can be injected through the client Transport.
+func NewHTTPFetcher(client *http.Client, userAgent string) *HTTPFetcher {
+	return fetcher.NewHTTPFetcher(client, userAgent)
+}
+
+// HtmlRequester fetches the web pages with the default HTTP fetcher of a configuration
+//
+// Deprecated: use HTTPFetcher, or Configuration.Fetcher and Configuration.HTTPClient
+type HtmlRequester struct {
+	*HTTPFetcher
+}
+
+// NewHtmlRequester returns a requester fetching the pages as ExtractFromURL does
+//
+// Deprecated: use NewHTTPFetcher
+func NewHtmlRequester(config Configuration) HtmlRequester {
+	return HtmlRequester{NewWithConfig(config).httpFetcher()}
+}
+
+// RetryPolicy tells the HTTPFetcher when and how long to wait before trying a request again
+type RetryPolicy = fetcher.RetryPolicy
+
+// DefaultRetryPolicy returns a policy making 3 attempts for the usual transient failures
+func DefaultRetryPolicy() RetryPolicy {
+	return fetcher.DefaultRetryPolicy()
+}
+
+// IsTransientError tells whether a network error is likely to go away on its own
+func IsTransientError(err error) bool {
+	return fetcher.IsTransientError(err)
+}
+
+// Cache stores the fetched responses, keyed by requested URL
+type Cache = fetcher.Cache
+
+// CachedResponse is a response kept by a Cache, with its body already decoded
+type CachedResponse = fetcher.CachedResponse
+
+// Cache statuses reported by FetchResult.CacheStatus and FetchInfo.CacheStatus
+const (
+	CacheMiss        = fetcher.CacheMiss
+	CacheHit         = fetcher.CacheHit
+	CacheRevalidated = fetcher.CacheRevalidated
+)
+
+// MemoryCache is a Cache keeping the most recently used responses in memory
+type MemoryCache = fetcher.MemoryCache
+
+// DiskCache is a Cache storing each response as a JSON file in a directory
+type DiskCache = fetcher.DiskCache
+
+// NewMemoryCache returns an LRU cache holding up to capacity responses
+func NewMemoryCache(capacity int) *MemoryCache {
+	return fetcher.NewMemoryCache(capacity)
+}
+
+// NewDiskCache returns a cache storing the responses in dir, created when needed
+func NewDiskCache(dir string) *DiskCache {
+	return fetcher.NewDiskCache(dir)
+}
+
+// NewLayeredCache returns a Cache made of the given caches, the fastest first
+func NewLayeredCache(caches ...Cache) Cache {
+	return fetcher.NewLayeredCache(caches...)
+}
+
+// RobotsChecker fetches and caches the robots.txt file of each host, and tells whether
+// a user agent may fetch a URL. It can be shared by several Goose instances
+type RobotsChecker = fetcher.RobotsChecker
+
+// NewRobotsChecker returns a checker fetching the robots.txt files with f. When f is nil,
+// they are fetched with the user agent of the first check on each host, and those over
+// 500 KiB are considered unreachable
+func NewRobotsChecker(f Fetcher) *RobotsChecker {
+	return fetcher.NewRobotsChecker(f)
+}
+
+// NewRobotsFetcher returns a Fetcher refusing the URLs robots.txt forbids to the user agent,
+// e.g. to fetch pages in bulk before extracting them with ExtractFromRawHTML
+func NewRobotsFetcher(next Fetcher, checker *RobotsChecker, userAgent string) Fetcher {
+	return fetcher.NewRobotsFetcher(next, checker, userAgent)
+}
+
+// HostLimiter throttles the requests sent to each host. It can be shared by several Goose instances
+type HostLimiter = fetcher.HostLimiter
+
+// LimiterStats are the metrics of a HostLimiter, for all the hosts or a single one
+type LimiterStats = fetcher.LimiterStats
+
+// NewHostLimiter returns a limiter letting through, for each host, requestsPerSecond
+// requests per second with bursts of up to burst requests, and at most maxConcurrent
+// requests at once. A zero value disables the corresponding limit
+func NewHostLimiter(requestsPerSecond float64, burst int, maxConcurrent int) *HostLimiter {
+	return fetcher.NewHostLimiter(requestsPerSecond, burst, maxConcurrent)
+}
+
+// NewLimitedFetcher returns a Fetcher throttled by the limiter, spacing the requests by the
+// Crawl-delay robots gives for the user agent when robots is not nil
+func NewLimitedFetcher(next Fetcher, limiter *HostLimiter, robots *RobotsChecker, userAgent string) Fetcher {
+	return fetcher.NewLimitedFetcher(next, limiter, robots, userAgent)
+}
+
+// PersistentCookieJar is an http.CookieJar saving its cookies to a JSON file, so that they outlive the process
+type PersistentCookieJar = fetcher.PersistentCookieJar
+
+// NewPersistentCookieJar returns a jar loading its cookies from the file at path, when it
+// exists, and saving them there each time a response sets some
+func NewPersistentCookieJar(path string) (*PersistentCookieJar, error) {
+	return fetcher.NewPersistentCookieJar(path)
+}
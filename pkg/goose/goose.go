@@ -1,9 +1,21 @@
 package goose
 
 import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+
 	"github.com/pkg/errors"
+
+	"github.com/advancedlogic/GoOse/internal/fetcher"
 )
 
+// defaultCacheEntries is the number of responses kept in memory when caching in LocalStoragePath
+const defaultCacheEntries = 256
+
+// manifestContentTypes are the media types the web app manifests are served with
+var manifestContentTypes = []string{"application/manifest+json", "application/json", "text/json", "text/plain"}
+
 // Goose is the main entry point of the program
 type Goose struct {
 	config Configuration
@@ -11,13 +23,27 @@ type Goose struct {
 
 // New returns a new instance of the article extractor
 func New(args ...string) Goose {
-	return Goose{
-		config: GetDefaultConfiguration(args...),
-	}
+	return NewWithConfig(GetDefaultConfiguration(args...))
 }
 
-// NewWithConfig returns a new instance of the article extractor with configuration
+// NewWithConfig returns a new instance of the article extractor with configuration.
+// When LocalStoragePath is set and Cache is not, the responses are cached in memory
+// and in that directory
 func NewWithConfig(config Configuration) Goose {
+	if config.HTTPClient == nil {
+		config.HTTPClient = defaultHTTPClient(config)
+	}
+	if config.ContentHandlers != nil {
+		// the handlers are looked up by the media type of the responses, in lowercase
+		handlers := make(map[string]ContentHandler, len(config.ContentHandlers))
+		for mediaType, handler := range config.ContentHandlers {
+			handlers[fetcher.MediaType(mediaType)] = handler
+		}
+		config.ContentHandlers = handlers
+	}
+	if config.Cache == nil && config.LocalStoragePath != "" {
+		config.Cache = NewLayeredCache(NewMemoryCache(defaultCacheEntries), NewDiskCache(config.LocalStoragePath))
+	}
 	return Goose{
 		config,
 	}
@@ -25,17 +51,128 @@ func NewWithConfig(config Configuration) Goose {
 
 // ExtractFromURL follows the URL, fetches the HTML page and returns an article object
 func (g Goose) ExtractFromURL(url string) (*Article, error) {
-	HtmlRequester := NewHtmlRequester(g.config)
-	html, err := HtmlRequester.fetchHTML(url)
+	return g.ExtractFromURLContext(context.Background(), url)
+}
+
+// ExtractFromURLContext is like ExtractFromURL but aborts the request and the extraction
+// as soon as the context is cancelled or its deadline expires
+func (g Goose) ExtractFromURLContext(ctx context.Context, url string) (*Article, error) {
+	result, err := fetcher.FetchFollowingRefresh(ctx, g.fetcher(), url, g.config.MaxMetaRefreshHops)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get htnk from site")
 	}
-	cc := NewCrawler(g.config)
-	return cc.Crawl(html, url)
+
+	var article *Article
+	contentType := result.Header.Get("Content-Type")
+	if fetcher.IsHTML(contentType) {
+		cc := NewCrawler(g.config)
+		cc.SetCharset(contentType)
+		cc.SetFetchTime(result.Header.Get("Date"))
+		cc.ManifestFetcher = g.manifestFetcher()
+		article, err = cc.CrawlContext(ctx, string(result.Body), result.FinalURL)
+	} else if handler := g.config.ContentHandlers[fetcher.MediaType(contentType)]; handler != nil {
+		article, err = handler(ctx, result)
+	} else {
+		err = &UnsupportedContentTypeError{URL: result.FinalURL, ContentType: contentType}
+	}
+	if err != nil {
+		return nil, err
+	}
+	article.Fetch = &FetchInfo{
+		RequestedURL: url,
+		FinalURL:     result.FinalURL,
+		Redirects:    result.Redirects,
+		StatusCode:   result.StatusCode,
+		ContentType:  result.Header.Get("Content-Type"),
+		LastModified: result.Header.Get("Last-Modified"),
+		ETag:         result.Header.Get("ETag"),
+		Retries:      result.Retries,
+		CacheStatus:  result.CacheStatus,
+		QueueWait:    result.QueueWait,
+	}
+	return article, nil
 }
 
 // ExtractFromRawHTML returns an article object from the raw HTML content
 func (g Goose) ExtractFromRawHTML(RawHTML string, url string) (*Article, error) {
+	return g.ExtractFromRawHTMLContext(context.Background(), RawHTML, url)
+}
+
+// ExtractFromRawHTMLContext is like ExtractFromRawHTML but aborts the extraction
+// as soon as the context is cancelled or its deadline expires
+func (g Goose) ExtractFromRawHTMLContext(ctx context.Context, RawHTML string, url string) (*Article, error) {
 	cc := NewCrawler(g.config)
-	return cc.Crawl(RawHTML, url)
+	cc.ManifestFetcher = g.manifestFetcher()
+	return cc.CrawlContext(ctx, RawHTML, url)
+}
+
+// fetcher returns the configured Fetcher, or the default HTTP one, checking robots.txt
+// and waiting for the host limiter first if asked to
+func (g Goose) fetcher() Fetcher {
+	f := g.config.Fetcher
+	if f == nil {
+		f = g.httpFetcher()
+	}
+	return g.politeFetcher(f)
+}
+
+// manifestFetcher returns the fetcher of the web app manifests, nil unless FetchWebManifest is set
+func (g Goose) manifestFetcher() Fetcher {
+	if !g.config.FetchWebManifest {
+		return nil
+	}
+	f := g.config.Fetcher
+	if f == nil {
+		httpFetcher := g.httpFetcher()
+		httpFetcher.ContentTypes = append([]string{}, manifestContentTypes...)
+		f = httpFetcher
+	}
+	return g.politeFetcher(f)
+}
+
+// politeFetcher wraps the fetcher to check robots.txt and wait for the host limiter, if asked to
+func (g Goose) politeFetcher(f Fetcher) Fetcher {
+	userAgent := g.config.RobotsUserAgent
+	if userAgent == "" {
+		userAgent = g.config.BrowserUserAgent
+	}
+	if g.config.Limiter != nil {
+		f = fetcher.NewLimitedFetcher(f, g.config.Limiter, g.config.Robots, userAgent)
+	}
+	if g.config.Robots != nil {
+		robotsFetcher := fetcher.NewRobotsFetcher(f, g.config.Robots, userAgent)
+		// the limiter spaces the requests by the Crawl-delay itself, and reports the wait
+		robotsFetcher.IgnoreCrawlDelay = g.config.Limiter != nil
+		f = robotsFetcher
+	}
+	return f
+}
+
+// httpFetcher returns the default HTTP fetcher, using the configured client
+func (g Goose) httpFetcher() *HTTPFetcher {
+	client := g.config.HTTPClient
+	if client == nil {
+		client = defaultHTTPClient(g.config)
+	}
+	f := NewHTTPFetcher(client, g.config.BrowserUserAgent)
+	f.MaxBodySize = g.config.MaxBodySize
+	f.MaxDecompressedSize = g.config.MaxDecompressedSize
+	f.RetryPolicy = g.config.RetryPolicy
+	f.Cache = g.config.Cache
+	f.PresetCookies = g.config.ConsentCookies
+	f.ContentTypes = append([]string{}, fetcher.HTMLContentTypes...)
+	for mediaType := range g.config.ContentHandlers {
+		f.ContentTypes = append(f.ContentTypes, mediaType)
+	}
+	return f
+}
+
+// defaultHTTPClient returns the client of the default fetcher when none is configured
+func defaultHTTPClient(config Configuration) *http.Client {
+	jar := config.CookieJar
+	if jar == nil {
+		// lets the pages setting a cookie before redirecting be fetched
+		jar, _ = cookiejar.New(nil)
+	}
+	return &http.Client{Timeout: config.Timeout, Jar: jar}
 }
@@ -0,0 +1,379 @@
+package goose
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+func Test_ExtractFromRawHTMLContextCancelled(t *testing.T) {
+	article := Article{Domain: "example.com"}
+	html := ReadRawHTML(article)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := New().ExtractFromRawHTMLContext(ctx, html, "http://www.example.com/index.html")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func Test_ExtractFromURLContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := New().ExtractFromURLContext(ctx, ts.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("extraction was not aborted by the deadline (took %s)", elapsed)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func Test_HTTPFetcherWithInjectedClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html><head><title>" + r.Header.Get("User-Agent") + "</title></head></html>"))
+	}))
+	defer ts.Close()
+
+	requests := 0
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+
+	config := GetDefaultConfiguration()
+	config.Fetcher = NewHTTPFetcher(client, "goose-test/1.0")
+	article, err := NewWithConfig(config).ExtractFromURL(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("expected the injected transport to be used once, got %d", requests)
+	}
+	if article.Title != "goose-test/1.0" {
+		t.Errorf("expected the User-Agent to be sent, got title %q", article.Title)
+	}
+
+	result, err := config.Fetcher.Fetch(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.StatusCode != http.StatusOK || result.FinalURL != ts.URL || result.Header.Get("Content-Type") != "text/html; charset=utf-8" {
+		t.Errorf("unexpected fetch result %d %q %q", result.StatusCode, result.FinalURL, result.Header.Get("Content-Type"))
+	}
+}
+
+func Test_ConfigurationHTTPClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html><head><title>" + strings.Repeat("a", 100) + "</title></head></html>"))
+	}))
+	defer ts.Close()
+
+	requests := 0
+	config := GetDefaultConfiguration()
+	config.HTTPClient = &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	config.MaxBodySize = 50
+	if _, err := NewWithConfig(config).ExtractFromURL(ts.URL); !errors.Is(err, ErrBodyTooLarge) || requests != 1 {
+		t.Errorf("expected the injected client to be used with the size limits, got %d requests and error %v", requests, err)
+	}
+
+	if New().config.HTTPClient == nil {
+		t.Errorf("expected the default client to be built with the instance")
+	}
+
+	result, err := NewHtmlRequester(GetDefaultConfiguration()).Fetch(context.Background(), ts.URL)
+	if err != nil || result.StatusCode != http.StatusOK {
+		t.Errorf("expected the deprecated requester to fetch the page, got %v", err)
+	}
+}
+
+type staticFetcher string
+
+func (f staticFetcher) Fetch(ctx context.Context, url string) (*FetchResult, error) {
+	return &FetchResult{Body: []byte(f), StatusCode: http.StatusOK, FinalURL: url}, nil
+}
+
+func Test_CustomFetcher(t *testing.T) {
+	config := GetDefaultConfiguration()
+	config.Fetcher = staticFetcher("<html><head><title>static</title></head></html>")
+	article, err := NewWithConfig(config).ExtractFromURL("http://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Title != "static" {
+		t.Errorf("expected the custom fetcher content, got title %q", article.Title)
+	}
+}
+
+func Test_ExtractFromURLRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><head><meta http-equiv="Refresh" content="0; URL='/c'"></head></html>`))
+	})
+	mux.HandleFunc("/c", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.Header().Set("ETag", `"33a64df5"`)
+		w.Write([]byte("<html><head><title>final</title></head></html>"))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	article, err := New().ExtractFromURL(ts.URL + "/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.FinalURL != ts.URL+"/c" {
+		t.Errorf("expected final URL %q, got %q", ts.URL+"/c", article.FinalURL)
+	}
+	expected := FetchInfo{
+		RequestedURL: ts.URL + "/a",
+		FinalURL:     ts.URL + "/c",
+		Redirects:    []string{ts.URL + "/a", ts.URL + "/b"},
+		StatusCode:   http.StatusOK,
+		ContentType:  "text/html; charset=utf-8",
+		LastModified: "Wed, 21 Oct 2015 07:28:00 GMT",
+		ETag:         `"33a64df5"`,
+	}
+	if article.Fetch == nil || !reflect.DeepEqual(*article.Fetch, expected) {
+		t.Errorf("unexpected fetch info %#v", article.Fetch)
+	}
+
+	config := GetDefaultConfiguration()
+	config.MaxMetaRefreshHops = 0
+	article, err = NewWithConfig(config).ExtractFromURL(ts.URL + "/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.FinalURL != ts.URL+"/b" {
+		t.Errorf("expected meta refresh not to be followed, got %q", article.FinalURL)
+	}
+}
+
+func Test_ContentTypeGuard(t *testing.T) {
+	served := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-1.4"))
+	}))
+	defer ts.Close()
+
+	_, err := New().ExtractFromURL(ts.URL)
+	var contentTypeErr *UnsupportedContentTypeError
+	if !errors.As(err, &contentTypeErr) || contentTypeErr.ContentType != "application/pdf" {
+		t.Errorf("expected an UnsupportedContentTypeError, got %v", err)
+	}
+
+	config := GetDefaultConfiguration()
+	// the media types of the handlers are matched whatever their case
+	config.ContentHandlers = map[string]ContentHandler{
+		"Application/PDF": func(ctx context.Context, result *FetchResult) (*Article, error) {
+			return &Article{Title: string(result.Body), FinalURL: result.FinalURL}, nil
+		},
+	}
+	article, err := NewWithConfig(config).ExtractFromURL(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Title != "%PDF-1.4" || article.Fetch.ContentType != "application/pdf" {
+		t.Errorf("expected the handler to build the article, got %q (%q)", article.Title, article.Fetch.ContentType)
+	}
+	if served != 2 {
+		t.Errorf("expected 2 requests, got %d", served)
+	}
+}
+
+func Test_BodySizeLimits(t *testing.T) {
+	page := "<html><head><title>big</title></head><body>" + strings.Repeat("<p>lorem ipsum</p>", 1000) + "</body></html>"
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte(page))
+	zw.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/gzip" {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(compressed.Bytes())
+			return
+		}
+		w.(http.Flusher).Flush() // no Content-Length
+		io.WriteString(w, page)
+	}))
+	defer ts.Close()
+
+	config := GetDefaultConfiguration()
+	config.MaxBodySize = 1024
+	if _, err := NewWithConfig(config).ExtractFromURL(ts.URL); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("expected ErrBodyTooLarge, got %v", err)
+	}
+
+	config.MaxBodySize = int64(compressed.Len())
+	config.MaxDecompressedSize = 1024
+	if _, err := NewWithConfig(config).ExtractFromURL(ts.URL + "/gzip"); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("expected ErrBodyTooLarge once decompressed, got %v", err)
+	}
+
+	config.MaxDecompressedSize = int64(len(page))
+	article, err := NewWithConfig(config).ExtractFromURL(ts.URL + "/gzip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Title != "big" {
+		t.Errorf("expected title %q, got %q", "big", article.Title)
+	}
+}
+
+func Test_CompressedBodies(t *testing.T) {
+	page := []byte("<html><head><title>compressed</title></head></html>")
+	encoders := map[string]func(io.Writer) io.WriteCloser{
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"br":      func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) },
+		"raw-deflate": func(w io.Writer) io.WriteCloser {
+			fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return fw
+		},
+	}
+	for name, encoder := range encoders {
+		var body bytes.Buffer
+		ew := encoder(&body)
+		ew.Write(page)
+		ew.Close()
+
+		encoding := strings.TrimPrefix(name, "raw-")
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.Contains(r.Header.Get("Accept-Encoding"), encoding) {
+				t.Errorf("%s: not accepted by %q", name, r.Header.Get("Accept-Encoding"))
+			}
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Encoding", encoding)
+			w.Write(body.Bytes())
+		}))
+
+		article, err := New().ExtractFromURL(ts.URL)
+		ts.Close()
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if article.Title != "compressed" {
+			t.Errorf("%s: expected title %q, got %q", name, "compressed", article.Title)
+		}
+	}
+}
+
+func Test_RetryPolicy(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/throttled" && requests == 1:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case r.URL.Path == "/flaky" && requests < 3:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Write([]byte("<html><head><title>ok</title></head></html>"))
+		}
+	}))
+	defer ts.Close()
+
+	config := GetDefaultConfiguration()
+	if _, err := NewWithConfig(config).ExtractFromURL(ts.URL + "/flaky"); err == nil || requests != 1 {
+		t.Errorf("expected no retry by default, got %d requests and error %v", requests, err)
+	}
+
+	requests = 0
+	config.RetryPolicy = DefaultRetryPolicy()
+	config.RetryPolicy.BaseDelay = time.Millisecond
+	g := NewWithConfig(config)
+
+	article, err := g.ExtractFromURL(ts.URL + "/flaky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 || article.Fetch.Retries != 2 {
+		t.Errorf("expected 2 retries, got %d requests and %d retries", requests, article.Fetch.Retries)
+	}
+
+	requests = 0
+	if _, err = g.ExtractFromURL(ts.URL + "/missing"); err == nil || requests != 1 {
+		t.Errorf("expected a 404 not to be retried, got %d requests and error %v", requests, err)
+	}
+
+	requests = 0
+	start := time.Now()
+	article, err = g.ExtractFromURL(ts.URL + "/throttled")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second || article.Fetch.Retries != 1 {
+		t.Errorf("expected Retry-After to be honoured, waited %s for %d retries", elapsed, article.Fetch.Retries)
+	}
+
+	requests = 0
+	config.RetryPolicy.MaxRetryAfter = 100 * time.Millisecond
+	if _, err = NewWithConfig(config).ExtractFromURL(ts.URL + "/throttled"); err == nil || requests != 1 {
+		t.Errorf("expected a too long Retry-After to fail at once, got %d requests and error %v", requests, err)
+	}
+
+	failures := 0
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if failures < 1 {
+			failures++
+			return nil, syscall.ECONNRESET
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	f := NewHTTPFetcher(client, "")
+	f.RetryPolicy = config.RetryPolicy
+	result, err := f.Fetch(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Retries != 1 {
+		t.Errorf("expected the reset connection to be retried once, got %d", result.Retries)
+	}
+}
@@ -0,0 +1,114 @@
+package goose
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const iconsPage = `<html><head><title>Parks</title>
+<link rel="shortcut icon" href="/favicon.ico">
+<link rel="icon" type="image/png" sizes="16x16 32x32" href="/icons/small.png">
+<link rel="icon" type="image/png" sizes="192X192" href="https://cdn.example.com/icons/192.png">
+<link rel="apple-touch-icon-precomposed" sizes="180x180" href="touch.png">
+<link rel="mask-icon" href="/pinned.svg" color="#5bbad5">
+<link rel="manifest" href="/site.webmanifest">
+<link rel="stylesheet" href="/style.css">
+</head><body>` + markupParagraph + `</body></html>`
+
+const iconsManifest = `{"name": "Parks", "icons": [
+{"src": "android-64.png", "sizes": "64x64", "type": "image/png", "purpose": "maskable"},
+{"src": "/android-512.png", "sizes": "512x512", "type": "image/png"},
+{"src": "logo.svg", "sizes": "any", "type": "image/svg+xml", "purpose": "any monochrome"}]}`
+
+func Test_Icons(t *testing.T) {
+	article, err := New().ExtractFromRawHTML(iconsPage, "https://example.com/news/parks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Icon{
+		{URL: "https://example.com/favicon.ico", Rel: IconRelIcon},
+		{URL: "https://example.com/icons/small.png", Rel: IconRelIcon, Type: "image/png", Sizes: []IconSize{{Width: 16, Height: 16}, {Width: 32, Height: 32}}},
+		{URL: "https://cdn.example.com/icons/192.png", Rel: IconRelIcon, Type: "image/png", Sizes: []IconSize{{Width: 192, Height: 192}}},
+		{URL: "https://example.com/news/touch.png", Rel: IconRelAppleTouchIcon, Sizes: []IconSize{{Width: 180, Height: 180}}},
+		{URL: "https://example.com/pinned.svg", Rel: IconRelMaskIcon, Scalable: true},
+	}
+	if !reflect.DeepEqual(article.Icons, expected) {
+		t.Errorf("expected the icons\n%+v\ngot\n%+v", expected, article.Icons)
+	}
+	if article.MetaFavicon != "https://example.com/favicon.ico" {
+		t.Errorf("expected an absolute favicon, got %s", article.MetaFavicon)
+	}
+}
+
+func Test_ManifestIcons(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/news/parks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(iconsPage))
+	})
+	mux.HandleFunc("/site.webmanifest", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/manifest+json")
+		w.Write([]byte(iconsManifest))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	article, err := New().ExtractFromURL(ts.URL + "/news/parks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(article.Icons) != 5 {
+		t.Errorf("expected the manifest not to be fetched by default, got %+v", article.Icons)
+	}
+
+	config := GetDefaultConfiguration()
+	config.FetchWebManifest = true
+	article, err = NewWithConfig(config).ExtractFromURL(ts.URL + "/news/parks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Icon{
+		{URL: ts.URL + "/android-64.png", Rel: IconRelManifest, Type: "image/png", Sizes: []IconSize{{Width: 64, Height: 64}}, Purpose: "maskable"},
+		{URL: ts.URL + "/android-512.png", Rel: IconRelManifest, Type: "image/png", Sizes: []IconSize{{Width: 512, Height: 512}}},
+		{URL: ts.URL + "/logo.svg", Rel: IconRelManifest, Type: "image/svg+xml", Scalable: true, Purpose: "any monochrome"},
+	}
+	if len(article.Icons) != 8 || !reflect.DeepEqual(article.Icons[5:], expected) {
+		t.Errorf("expected the manifest icons\n%+v\ngot\n%+v", expected, article.Icons)
+	}
+}
+
+func Test_BestIcon(t *testing.T) {
+	icons := []Icon{
+		{URL: "favicon.ico", Rel: IconRelIcon},
+		{URL: "small.png", Rel: IconRelIcon, Sizes: []IconSize{{Width: 16, Height: 16}, {Width: 32, Height: 32}}},
+		{URL: "touch.png", Rel: IconRelAppleTouchIcon, Sizes: []IconSize{{Width: 180, Height: 180}}},
+		{URL: "192.png", Rel: IconRelIcon, Sizes: []IconSize{{Width: 192, Height: 192}}},
+		{URL: "pinned.svg", Rel: IconRelMaskIcon, Scalable: true},
+		{URL: "maskable-64.png", Rel: IconRelManifest, Sizes: []IconSize{{Width: 64, Height: 64}}, Purpose: "maskable"},
+	}
+	tests := []struct {
+		icons    []Icon
+		size     int
+		expected string
+	}{
+		{icons, 32, "small.png"},
+		{icons, 64, "touch.png"},
+		{icons, 190, "192.png"},
+		{icons, 256, "192.png"},
+		{append(icons, Icon{URL: "logo.svg", Rel: IconRelIcon, Scalable: true}), 64, "logo.svg"},
+		{append(icons, Icon{URL: "64.png", Rel: IconRelManifest, Sizes: []IconSize{{Width: 64, Height: 64}}, Purpose: "any maskable"}), 64, "64.png"},
+		{icons[:1], 64, "favicon.ico"},
+		{icons[4:], 64, "maskable-64.png"},
+	}
+	for _, test := range tests {
+		best := BestIcon(test.icons, test.size)
+		if best == nil || best.URL != test.expected {
+			t.Errorf("%dpx: expected %s, got %+v", test.size, test.expected, best)
+		}
+	}
+	if BestIcon(nil, 64) != nil {
+		t.Error("expected no icon")
+	}
+}
@@ -0,0 +1,150 @@
+package goose
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const jsonldPage = `<html><head><title>Council approves the parks budget | The Daily</title>
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "BreadcrumbList", "itemListElement": []}</script>
+<script type="application/ld+json">
+{
+	"@context": "https://schema.org",
+	"@graph": [
+		{"@type": "WebPage", "@id": "https://example.com/parks#webpage", "mainEntity": {"@id": "https://example.com/parks#article"}},
+		{"@type": ["NewsArticle"], "@id": "https://example.com/parks#article",
+			"headline": "Council approves the parks budget &amp; new playgrounds",
+			"author": [{"@id": "https://example.com/#jane"}, {"@type": "Person", "name": "John Roe", "url": "/authors/john"}, "Desk Staff"],
+			"publisher": {"@type": "Organization", "name": "The Daily", "logo": {"@type": "ImageObject", "url": "/logo.png"}},
+			"datePublished": "2024-03-05T09:30:00+01:00",
+			"dateModified": "2024-03-06T10:00:00+01:00",
+			"image": [{"@type": "ImageObject", "url": "/images/park.jpg"}, "/images/park-small.jpg"],
+			"articleSection": "Local",
+			"keywords": "parks, budget,council",
+			"isAccessibleForFree": "False",
+		},
+		{"@type": "Person", "@id": "https://example.com/#jane", "name": "Jane Doe", "sameAs": ["https://social.example/jane"]}
+	]
+}
+</script></head>
+<body><article><p>` + "The council approved the new budget for the city parks after a long debate." + `</p></article></body></html>`
+
+func Test_JSONLD(t *testing.T) {
+	article, err := New().ExtractFromRawHTML(jsonldPage, "https://example.com/parks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata := article.Metadata
+	if metadata == nil {
+		t.Fatal("expected the JSON-LD metadata to be parsed")
+	}
+	if metadata.Type != "NewsArticle" || metadata.Headline != "Council approves the parks budget & new playgrounds" {
+		t.Errorf("unexpected type and headline %q, %q", metadata.Type, metadata.Headline)
+	}
+	expectedAuthors := []Entity{
+		{Type: "Person", Name: "Jane Doe", URL: "https://social.example/jane"},
+		{Type: "Person", Name: "John Roe", URL: "https://example.com/authors/john"},
+		{Name: "Desk Staff"},
+	}
+	if len(metadata.Authors) != len(expectedAuthors) {
+		t.Fatalf("expected %d authors, got %+v", len(expectedAuthors), metadata.Authors)
+	}
+	for i, author := range expectedAuthors {
+		if metadata.Authors[i] != author {
+			t.Errorf("expected author %+v, got %+v", author, metadata.Authors[i])
+		}
+	}
+	if metadata.Publisher == nil || metadata.Publisher.Name != "The Daily" || metadata.Publisher.Logo != "https://example.com/logo.png" {
+		t.Errorf("unexpected publisher %+v", metadata.Publisher)
+	}
+	published := time.Date(2024, 3, 5, 9, 30, 0, 0, time.FixedZone("", 3600))
+	if metadata.DatePublished == nil || !metadata.DatePublished.Equal(published) || metadata.DateModified == nil {
+		t.Errorf("unexpected dates %v, %v", metadata.DatePublished, metadata.DateModified)
+	}
+	if _, offset := metadata.DatePublished.Zone(); offset != 3600 {
+		t.Errorf("expected the time zone offset to be kept, got %d", offset)
+	}
+	if metadata.Image != "https://example.com/images/park.jpg" {
+		t.Errorf("unexpected image %q", metadata.Image)
+	}
+	if strings.Join(metadata.ArticleSection, "|") != "Local" || strings.Join(metadata.Keywords, "|") != "parks|budget|council" {
+		t.Errorf("unexpected section %v and keywords %v", metadata.ArticleSection, metadata.Keywords)
+	}
+	if metadata.IsAccessibleForFree == nil || *metadata.IsAccessibleForFree {
+		t.Errorf("expected the article not to be accessible for free")
+	}
+
+	// the JSON-LD takes precedence over the heuristics, its headline completing the title
+	if article.Title != metadata.Headline {
+		t.Errorf("expected the headline as title, got %q", article.Title)
+	}
+	if article.PublishDate == nil || !article.PublishDate.Equal(published) {
+		t.Errorf("expected the JSON-LD publish date, got %v", article.PublishDate)
+	}
+	if article.TopImage != metadata.Image {
+		t.Errorf("expected the JSON-LD image as top image, got %q", article.TopImage)
+	}
+}
+
+func Test_JSONLDHeadline(t *testing.T) {
+	tests := []struct {
+		title    string
+		headline string
+		expected string
+	}{
+		// a headline written for the social networks
+		{"Gmail Will Soon Warn Users When Emails Arrive Over Unencrypted Connections | TechCrunch",
+			"Gmail Improves Email Security", "Gmail Will Soon Warn Users When Emails Arrive Over Unencrypted Connections"},
+		// a truncated title
+		{"Council approves the parks budget… | The Daily",
+			"Council approves the parks budget and three new playgrounds", "Council approves the parks budget and three new playgrounds"},
+		{"", "Council approves the parks budget", "Council approves the parks budget"},
+	}
+	for _, test := range tests {
+		page := `<html><head><title>` + test.title + `</title><script type="application/ld+json">
+{"@context": "https://schema.org", "@type": "NewsArticle", "headline": "` + test.headline + `"}</script></head></html>`
+		article, err := New().ExtractFromRawHTML(page, "https://example.com/parks")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if article.Title != test.expected {
+			t.Errorf("%q, %q: expected the title %q, got %q", test.title, test.headline, test.expected, article.Title)
+		}
+	}
+}
+
+func Test_JSONLDTopImage(t *testing.T) {
+	page := `<html><head><title>Parks</title>%s
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "NewsArticle",
+"headline": "Parks", "image": "/images/park-small.jpg"}</script></head>
+<body><article><p>The council approved the new budget for the city parks after a long debate.</p>
+<img src="/images/council.jpg" width="800" height="600"></article></body></html>`
+	tests := []struct {
+		meta     string
+		expected string
+	}{
+		{`<meta property="og:image" content="https://example.com/images/park-large.jpg">`, "https://example.com/images/park-large.jpg"},
+		{"", "https://example.com/images/park-small.jpg"},
+	}
+	for _, test := range tests {
+		article, err := New().ExtractFromRawHTML(strings.Replace(page, "%s", test.meta, 1), "https://example.com/parks")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if article.TopImage != test.expected {
+			t.Errorf("%q: expected the top image %q, got %q", test.meta, test.expected, article.TopImage)
+		}
+	}
+}
+
+func Test_JSONLDMissing(t *testing.T) {
+	article, err := New().ExtractFromRawHTML(`<html><head><title>No metadata</title>
+<script type="application/ld+json">{"@type": "Organization", "name": "The Daily"}</script></head></html>`, "https://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Metadata != nil || article.Title != "No metadata" {
+		t.Errorf("expected no article metadata, got %+v", article.Metadata)
+	}
+}
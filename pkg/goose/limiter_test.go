@@ -0,0 +1,122 @@
+package goose
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_HostLimiter(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		w.Write([]byte("<html><head><title>limited</title></head></html>"))
+	}))
+	defer ts.Close()
+
+	// the limiter is shared by two instances
+	limiter := NewHostLimiter(20, 2, 2)
+	config := GetDefaultConfiguration()
+	config.Limiter = limiter
+	instances := []Goose{NewWithConfig(config), NewWithConfig(config)}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(g Goose) {
+			defer wg.Done()
+			if _, err := g.ExtractFromURL(ts.URL + "/page"); err != nil {
+				t.Error(err)
+			}
+		}(instances[i%2])
+	}
+	wg.Wait()
+
+	// 2 requests in the burst, then 4 more at 20 per second
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("expected the rate to be limited, took %s", elapsed)
+	}
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 concurrent requests, got %d", maxInFlight)
+	}
+	host := strings.TrimPrefix(ts.URL, "http://")
+	stats := limiter.HostStats(host)
+	if stats.Requests != 6 || stats.Waiting != 0 || stats.MaxWait < 100*time.Millisecond || stats.TotalWait < stats.MaxWait {
+		t.Errorf("unexpected host stats %+v", stats)
+	}
+	if limiter.Stats() != stats {
+		t.Errorf("expected the global stats %+v to match the only host", limiter.Stats())
+	}
+}
+
+func Test_HostLimiterCrawlDelay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.Write([]byte("User-agent: *\nCrawl-delay: 0.1\n"))
+			return
+		}
+		w.Write([]byte("<html><head><title>limited</title></head></html>"))
+	}))
+	defer ts.Close()
+
+	config := GetDefaultConfiguration()
+	config.Fetcher = NewLimitedFetcher(NewHTTPFetcher(http.DefaultClient, ""), NewHostLimiter(0, 10, 0), NewRobotsChecker(nil), "goosebot")
+	g := NewWithConfig(config)
+	var waited time.Duration
+	for i := 0; i < 3; i++ {
+		article, err := g.ExtractFromURL(ts.URL + "/page")
+		if err != nil {
+			t.Fatal(err)
+		}
+		waited += article.Fetch.QueueWait
+	}
+	if waited < 180*time.Millisecond {
+		t.Errorf("expected the Crawl-delay to space the requests, waited %s", waited)
+	}
+}
+
+func Test_HostLimiterRobotsCrawlDelay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.Write([]byte("User-agent: *\nCrawl-delay: 0.1\n"))
+			return
+		}
+		w.Write([]byte("<html><head><title>limited</title></head></html>"))
+	}))
+	defer ts.Close()
+
+	// the Crawl-delay is only waited for in the limiter, which reports it
+	config := GetDefaultConfiguration()
+	config.Robots = NewRobotsChecker(nil)
+	config.Limiter = NewHostLimiter(0, 10, 0)
+	g := NewWithConfig(config)
+	start := time.Now()
+	var waited time.Duration
+	for i := 0; i < 3; i++ {
+		article, err := g.ExtractFromURL(ts.URL + "/page")
+		if err != nil {
+			t.Fatal(err)
+		}
+		waited += article.Fetch.QueueWait
+	}
+	if elapsed := time.Since(start); waited < 180*time.Millisecond || elapsed > waited+100*time.Millisecond {
+		t.Errorf("expected the Crawl-delay to be reported as queue wait, waited %s of %s", waited, elapsed)
+	}
+	if stats := config.Limiter.Stats(); stats.TotalWait != waited {
+		t.Errorf("expected the limiter stats to report the %s wait, got %+v", waited, stats)
+	}
+}
@@ -0,0 +1,75 @@
+package goose
+
+import "testing"
+
+func Test_Markdown(t *testing.T) {
+	article, err := New().ExtractFromRawHTML(blocksPage, "https://example.com/news/parks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "# Parks budget approved\n\n" +
+		"## A long debate\n\n" +
+		"The council approved the new budget for the [city parks](https://example.com/tags/parks) after a long debate on Tuesday evening.\n\n" +
+		"The plan, which was *first presented* in March, funds three new playgrounds and the repair of the old fountains.\n\n" +
+		"- Three new playgrounds in the **northern districts** of the city\n" +
+		"- The repair of the fountains, [listed as heritage](https://example.org/fountains) since 1990\n" +
+		"  1. The fountain of the main square and the one of the station\n\n" +
+		"> We have waited for this budget for a very long time now.\\\n" +
+		"> It is good news for the families of the city.\n\n" +
+		"```go\ntotal := parks + fountains\nfmt.Println(total)\n```\n\n" +
+		"Budget by item\n\n" +
+		"| Item | Amount |\n| --- | --- |\n| Playgrounds | 1.2M |\n| Fountains | 0.8M |\n\n" +
+		"![The park](https://example.com/images/park.jpg)\n\n" +
+		"The park of the city, seen from the northern gate of the old walls\n\n" +
+		"<https://www.youtube.com/embed/abc123>\n\n" +
+		"The works will start in the spring and should be over before the end of next year, the mayor said.\n"
+	if markdown := article.Markdown(); markdown != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, markdown)
+	}
+}
+
+func Test_BlocksMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		block    Block
+		expected string
+	}{
+		{"escaped text", Block{Type: BlockParagraph, Text: "2 * 3 = 6, see [1] and snake_case"},
+			`2 \* 3 = 6, see \[1\] and snake\_case`},
+		{"escaped line starts", Block{Type: BlockParagraph, Text: "# not a heading\n1984. A year\n- not an item"},
+			"\\# not a heading\\\n1984\\. A year\\\n\\- not an item"},
+		{"nested styles and links", Block{Type: BlockParagraph, Text: "Read the new budget now",
+			Links:  []Link{{Text: "the new budget", Offset: 5, URL: "https://example.com/budget (2024)"}},
+			Styles: []Style{{Type: StyleStrong, Text: "Read the new budget", Offset: 0}, {Type: StyleEmphasis, Text: "new", Offset: 9}}},
+			"**Read [the *new* budget](https://example.com/budget%20%282024%29)** now"},
+		{"overlapping styles", Block{Type: BlockParagraph, Text: "one two three",
+			Styles: []Style{{Type: StyleStrong, Text: "one two", Offset: 0}, {Type: StyleEmphasis, Text: "two three", Offset: 4}}},
+			"**one two** three"},
+		{"code", Block{Type: BlockParagraph, Text: "Call f(*p) or `x`",
+			Styles: []Style{{Type: StyleCode, Text: "f(*p)", Offset: 5}, {Type: StyleCode, Text: "`x`", Offset: 14}}},
+			"Call `f(*p)` or `` `x` ``"},
+		{"not an image", Block{Type: BlockParagraph, Text: "Wow!parks",
+			Links: []Link{{Text: "parks", Offset: 4, URL: "https://example.com/parks"}}},
+			`Wow\![parks](https://example.com/parks)`},
+		{"bold heading", Block{Type: BlockHeading, Level: 3, Text: "Budget",
+			Styles: []Style{{Type: StyleStrong, Text: "Budget", Offset: 0}}},
+			"### Budget"},
+		{"nested lists", Block{Type: BlockList, Ordered: true, Items: []Block{
+			{Type: BlockListItem, Ordered: true, Text: "Parks"},
+			{Type: BlockListItem, Level: 1, Text: "North"},
+			{Type: BlockListItem, Level: 2, Ordered: true, Text: "Gate\nWalls"},
+			{Type: BlockListItem, Ordered: true, Text: "Fountains"},
+		}}, "1. Parks\n   - North\n     1. Gate\\\n        Walls\n2. Fountains"},
+		{"fenced code", Block{Type: BlockCode, Text: "```\nquoted\n```"},
+			"````\n```\nquoted\n```\n````"},
+		{"table without header", Block{Type: BlockTable, Rows: [][]string{{"a|b", "1"}, {"c"}}},
+			"|  |  |\n| --- | --- |\n| a\\|b | 1 |\n| c |  |"},
+		{"embed with caption", Block{Type: BlockEmbed, Text: "The council [live]", URL: "https://example.com/video"},
+			`[The council \[live\]](https://example.com/video)`},
+	}
+	for _, test := range tests {
+		if markdown := BlocksMarkdown([]Block{test.block}); markdown != test.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.name, test.expected, markdown)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package goose
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const markupParagraph = "<p>The council approved the new budget for the city parks after a long debate.</p>"
+
+func Test_MarkupMetadata(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		expected  Metadata
+		published string
+	}{
+		{
+			name: "microdata",
+			body: `<article itemscope itemtype="http://schema.org/BlogPosting">
+<h1 itemprop="headline">Parks budget approved</h1>
+<span itemprop="author creator" itemscope itemtype="http://schema.org/Person"><a itemprop="url" href="/jane"><span itemprop="name">Jane Doe</span></a></span>
+<time itemprop="datePublished" datetime="2024-03-05T09:30:00+01:00">5 March</time>
+<img itemprop="image" src="/park.jpg">
+<a itemprop="keywords" href="/tags/parks">parks</a>
+<div itemprop="publisher" itemscope itemtype="http://schema.org/Organization"><meta itemprop="name" content="The Daily">
+<span itemprop="logo" itemscope itemtype="http://schema.org/ImageObject"><meta itemprop="url" content="/logo.png"></span></div>
+` + markupParagraph + `</article>`,
+			expected: Metadata{
+				Type:                "BlogPosting",
+				Headline:            "Parks budget approved",
+				Authors:             []Entity{{Type: "Person", Name: "Jane Doe", URL: "https://example.com/jane"}},
+				DatePublishedSource: DateSourceMicrodata,
+				Publisher:           &Entity{Type: "Organization", Name: "The Daily", Logo: "https://example.com/logo.png"},
+				Image:               "https://example.com/park.jpg",
+				Keywords:            []string{"parks"},
+				Sources:             []string{MetadataSourceMicrodata},
+			},
+			published: "2024-03-05T09:30:00+01:00",
+		},
+		{
+			name: "RDFa",
+			body: `<article vocab="http://schema.org/" typeof="NewsArticle">
+<h1 property="headline">Parks budget approved</h1>
+<span property="author" typeof="Person"><span property="name">Jane Doe</span></span>
+<span property="dc:created" content="2024-03-05">5 March</span>
+` + markupParagraph + `</article>`,
+			expected: Metadata{
+				Type:                "NewsArticle",
+				Headline:            "Parks budget approved",
+				Authors:             []Entity{{Type: "Person", Name: "Jane Doe"}},
+				DatePublishedSource: DateSourceRDFa,
+				Sources:             []string{MetadataSourceRDFa},
+			},
+			published: "2024-03-05T00:00:00Z",
+		},
+		{
+			name: "microformats2",
+			body: `<article class="h-entry"><h1 class="p-name">Parks budget approved</h1>
+<a class="p-author h-card" href="/jane">Jane Doe</a>
+<time class="dt-published" datetime="2024-03-05T09:30:00Z">5 March</time>
+<span class="p-category">parks</span><img class="u-featured" src="/park.jpg">
+<div class="h-cite"><span class="p-name">Another article</span></div>
+` + markupParagraph + `</article>`,
+			expected: Metadata{
+				Type:                "h-entry",
+				Headline:            "Parks budget approved",
+				Authors:             []Entity{{Type: "h-card", Name: "Jane Doe", URL: "https://example.com/jane"}},
+				DatePublishedSource: DateSourceMicroformats,
+				Image:               "https://example.com/park.jpg",
+				Keywords:            []string{"parks"},
+				Sources:             []string{MetadataSourceMicroformats},
+			},
+			published: "2024-03-05T09:30:00Z",
+		},
+		{
+			name: "classic microformats",
+			body: `<div class="hentry"><h2 class="entry-title">Parks budget approved</h2>
+<span class="author vcard"><a class="url fn" href="/jane">Jane Doe</a></span>
+<abbr class="published" title="2024-03-05T09:30:00Z">5 March</abbr>
+` + markupParagraph + `</div>`,
+			expected: Metadata{
+				Type:                "h-entry",
+				Headline:            "Parks budget approved",
+				Authors:             []Entity{{Type: "h-card", Name: "Jane Doe", URL: "https://example.com/jane"}},
+				DatePublishedSource: DateSourceMicroformats,
+				Sources:             []string{MetadataSourceMicroformats},
+			},
+			published: "2024-03-05T09:30:00Z",
+		},
+		{
+			name: "precedence",
+			body: `<article itemscope itemtype="http://schema.org/Article"><h1 itemprop="headline">Microdata headline</h1>
+<img itemprop="image" src="/microdata.jpg"></article>
+<div class="h-entry"><span class="p-name">Microformats headline</span><span class="p-author h-card">Jane Doe</span>
+<time class="dt-published" datetime="2024-03-05T09:30:00Z"></time>` + markupParagraph + `</div>`,
+			expected: Metadata{
+				Type:                "Article",
+				Headline:            "Microdata headline",
+				Authors:             []Entity{{Type: "h-card", Name: "Jane Doe"}},
+				DatePublishedSource: DateSourceMicroformats,
+				Image:               "https://example.com/microdata.jpg",
+				Sources:             []string{MetadataSourceMicrodata, MetadataSourceMicroformats},
+			},
+			published: "2024-03-05T09:30:00Z",
+		},
+	}
+	for _, test := range tests {
+		page := "<html><head><title>Parks</title></head><body>" + test.body + "</body></html>"
+		article, err := New().ExtractFromRawHTML(page, "https://example.com/parks")
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if article.Metadata == nil {
+			t.Errorf("%s: expected some metadata", test.name)
+			continue
+		}
+		metadata := *article.Metadata
+		if metadata.DatePublished == nil || metadata.DatePublished.Format(time.RFC3339) != test.published {
+			t.Errorf("%s: expected the publish date %s, got %v", test.name, test.published, metadata.DatePublished)
+		}
+		metadata.DatePublished = nil
+		if !reflect.DeepEqual(metadata, test.expected) {
+			t.Errorf("%s: expected\n%+v\ngot\n%+v", test.name, test.expected, metadata)
+		}
+		if article.PublishDateSource == nil || article.PublishDateSource.Name != test.expected.DatePublishedSource {
+			t.Errorf("%s: expected the publish date to be read from %s, got %+v", test.name, test.expected.DatePublishedSource, article.PublishDateSource)
+		}
+	}
+}
+
+func Test_MarkupListing(t *testing.T) {
+	page := `<html><head><title>Blog</title></head><body>
+<article class="hentry"><h2 class="entry-title">First post</h2>` + markupParagraph + `</article>
+<article class="hentry"><h2 class="entry-title">Second post</h2>` + markupParagraph + `</article></body></html>`
+	article, err := New().ExtractFromRawHTML(page, "https://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Metadata != nil {
+		t.Errorf("expected no metadata for a page listing several articles, got %+v", article.Metadata)
+	}
+}
+
+func Test_MarkupAuthorName(t *testing.T) {
+	page := `<html><head><title>Parks</title></head><body><article itemscope itemtype="http://schema.org/NewsArticle">
+<meta itemprop="author" content="Jane Doe, The Daily"><a itemprop="author" href="/john">John Roe</a>
+<h1 itemprop="headline">Parks budget approved</h1>` + markupParagraph + `</article></body></html>`
+	article, err := New().ExtractFromRawHTML(page, "https://example.com/parks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Entity{{Name: "Jane Doe"}, {Name: "The Daily"}, {Name: "John Roe", URL: "https://example.com/john"}}
+	if article.Metadata == nil || !reflect.DeepEqual(article.Metadata.Authors, expected) {
+		t.Errorf("expected the authors %+v, got the metadata %+v", expected, article.Metadata)
+	}
+}
+
+func Test_MarkupFixture(t *testing.T) {
+	raw, err := ioutil.ReadFile("../../sites/blogspot.co.uk.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	article, err := New().ExtractFromRawHTML(string(raw), "http://googlewebmastercentral.blogspot.co.uk/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Metadata == nil || article.Metadata.Type != "BlogPosting" ||
+		article.Metadata.Headline != "Five ways to grow your business this Small Business Week" {
+		t.Errorf("unexpected metadata %+v", article.Metadata)
+	}
+}
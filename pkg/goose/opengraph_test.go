@@ -0,0 +1,90 @@
+package goose
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const openGraphPage = `<html><head><title>Parks</title>
+<meta property="og:type" content="article">
+<meta property="og:site_name" content="The Daily">
+<meta property="og:title" content="Council approves the parks budget">
+<meta name="og:description" content="A long debate &amp; a vote.">
+<meta property="og:url" content="/parks">
+<meta property="og:locale" content="en_GB">
+<meta property="article:published_time" content="2024-03-05T09:30:00+01:00">
+<meta property="article:modified_time" content="2024-03-06T10:00:00Z">
+<meta property="article:author" content="https://example.com/authors/jane">
+<meta property="article:author" content="John Roe">
+<meta property="article:section" content="Local">
+<meta property="article:tag" content="parks">
+<meta property="article:tag" content="budget">
+<meta property="og:image" content="https://example.com/park.jpg">
+<meta property="og:image:url" content="https://example.com/park.jpg">
+<meta property="og:image:width" content="1200">
+<meta property="og:image:height" content="630">
+<meta property="og:image:alt" content="The park">
+<meta property="og:image" content="/park-small.jpg">
+<meta property="og:image:secure_url" content="https://example.com/park-small.jpg">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:site" content="@thedaily">
+<meta name="twitter:creator" content="@jane">
+<meta name="twitter:title" content="Parks budget approved">
+<meta name="twitter:description" content="The vote">
+<meta name="twitter:image:src" value="/card.jpg">
+<meta name="twitter:image:alt" content="A park">
+</head><body></body></html>`
+
+func Test_OpenGraph(t *testing.T) {
+	article, err := New().ExtractFromRawHTML(openGraphPage, "https://example.com/news/parks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	og := article.OpenGraph
+	if og == nil {
+		t.Fatal("expected the Open Graph properties to be parsed")
+	}
+	if og.Type != "article" || og.SiteName != "The Daily" || og.Title != "Council approves the parks budget" ||
+		og.Description != "A long debate & a vote." || og.URL != "https://example.com/parks" || og.Locale != "en_GB" {
+		t.Errorf("unexpected properties %+v", og)
+	}
+	if og.PublishedTime == nil || !og.PublishedTime.Equal(time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC)) || og.ModifiedTime == nil {
+		t.Errorf("unexpected times %v, %v", og.PublishedTime, og.ModifiedTime)
+	}
+	if strings.Join(og.Authors, "|") != "https://example.com/authors/jane|John Roe" || og.Section != "Local" || strings.Join(og.Tags, "|") != "parks|budget" {
+		t.Errorf("unexpected article properties %v, %q, %v", og.Authors, og.Section, og.Tags)
+	}
+	expectedImages := []OpenGraphImage{
+		{URL: "https://example.com/park.jpg", Width: 1200, Height: 630, Alt: "The park"},
+		{URL: "https://example.com/park-small.jpg", SecureURL: "https://example.com/park-small.jpg"},
+	}
+	if len(og.Images) != len(expectedImages) {
+		t.Fatalf("expected %d images, got %+v", len(expectedImages), og.Images)
+	}
+	for i, image := range expectedImages {
+		if og.Images[i] != image {
+			t.Errorf("expected image %+v, got %+v", image, og.Images[i])
+		}
+	}
+
+	expectedCard := TwitterCard{
+		Card:        "summary_large_image",
+		Site:        "@thedaily",
+		Creator:     "@jane",
+		Title:       "Parks budget approved",
+		Description: "The vote",
+		Image:       "https://example.com/card.jpg",
+		ImageAlt:    "A park",
+	}
+	if article.TwitterCard == nil || *article.TwitterCard != expectedCard {
+		t.Errorf("expected the card %+v, got %+v", expectedCard, article.TwitterCard)
+	}
+
+	if article, err = New().ExtractFromRawHTML("<html><head><title>none</title></head></html>", ""); err != nil {
+		t.Fatal(err)
+	}
+	if article.OpenGraph != nil || article.TwitterCard != nil {
+		t.Errorf("expected no Open Graph nor Twitter Card, got %+v, %+v", article.OpenGraph, article.TwitterCard)
+	}
+}
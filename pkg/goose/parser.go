@@ -1,61 +1,22 @@
 package goose
 
-import "strings"
+import (
+	"github.com/advancedlogic/GoOse/internal/parser"
+	"github.com/advancedlogic/GoOse/internal/utils"
+)
 
-// StopWords is a simple stopwords implementation
-type StopWords struct {
-	// Simple implementation
-}
+// StopWords implements a simple language detector based on stop words
+type StopWords = utils.StopWords
 
-// NewStopwords creates a new stopwords instance
+// NewStopwords returns an instance of a stop words detector
 func NewStopwords() StopWords {
-	return StopWords{}
-}
-
-// SimpleLanguageDetector detects language (stub implementation)
-func (sw StopWords) SimpleLanguageDetector(text string) string {
-	return "en" // Default to English
-}
-
-// StopWordsCount counts stop words in the text (stub implementation)
-func (sw StopWords) StopWordsCount(language string, text string) int {
-	// Stub implementation - return a simple word count
-	words := strings.Fields(text)
-	return len(words) / 3 // Assume 1/3 are stop words
+	return utils.NewStopwords()
 }
 
-// Parser is a simple HTML parser
-type Parser struct {
-	// Simple implementation
-}
+// Parser is an HTML parser specialised in extraction of main content and other properties
+type Parser = parser.Parser
 
-// NewParser creates a new parser
+// NewParser returns an HTML parser
 func NewParser() *Parser {
-	return &Parser{}
-}
-
-// DelAttr removes an attribute from the selection
-func (p Parser) DelAttr(selection interface{}, attr string) {
-	// Stub implementation
-}
-
-// DropTag removes the tag but keeps its contents
-func (p Parser) DropTag(selection interface{}) {
-	// Stub implementation
-}
-
-// RemoveNode removes the entire node
-func (p Parser) RemoveNode(selection interface{}) {
-	// Stub implementation
-}
-
-// Name gets the value of an attribute
-func (p Parser) Name(selector string, selection interface{}) string {
-	// Stub implementation
-	return ""
-}
-
-// SetAttr sets an attribute on the selection
-func (p Parser) SetAttr(selection interface{}, attr string, value string) {
-	// Stub implementation
+	return parser.NewParser()
 }
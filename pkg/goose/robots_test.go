@@ -0,0 +1,148 @@
+package goose
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const robotsTxt = `# test rules
+User-agent: goosebot
+User-agent: otherbot
+Disallow: /private
+Allow: /private/open
+Disallow: /*.pdf$
+Crawl-delay: 0.2
+
+User-agent: *
+Disallow: /
+`
+
+func Test_RobotsChecker(t *testing.T) {
+	robotsRequests, robotsUserAgent := 0, ""
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			robotsRequests++
+			robotsUserAgent = r.Header.Get("User-Agent")
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(robotsTxt))
+			return
+		}
+		w.Write([]byte("<html><head><title>allowed</title></head></html>"))
+	}))
+	defer ts.Close()
+
+	config := GetDefaultConfiguration()
+	config.Robots = NewRobotsChecker(nil)
+	config.RobotsUserAgent = "GooseBot/1.0"
+	g := NewWithConfig(config)
+
+	start := time.Now()
+	for _, path := range []string{"/page", "/private/open/page"} {
+		article, err := g.ExtractFromURL(ts.URL + path)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if article.Title != "allowed" {
+			t.Errorf("%s: expected the page to be extracted, got %q", path, article.Title)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("expected the Crawl-delay to be honoured, took %s", elapsed)
+	}
+
+	for _, path := range []string{"/private/page", "/file.pdf"} {
+		_, err := g.ExtractFromURL(ts.URL + path)
+		var robotsErr *RobotsDisallowedError
+		if !errors.As(err, &robotsErr) || robotsErr.URL != ts.URL+path || robotsErr.UserAgent != "GooseBot/1.0" {
+			t.Errorf("%s: expected a RobotsDisallowedError, got %v", path, err)
+		}
+	}
+	if _, err := g.ExtractFromURL(ts.URL + "/file.pdf?download=1"); err != nil {
+		t.Errorf("expected the $ anchor not to match a query string, got %v", err)
+	}
+
+	// the other agents fall back to the * group
+	config.RobotsUserAgent = ""
+	_, err := NewWithConfig(config).ExtractFromURL(ts.URL + "/page")
+	var robotsErr *RobotsDisallowedError
+	if !errors.As(err, &robotsErr) || robotsErr.UserAgent != config.BrowserUserAgent {
+		t.Errorf("expected the browser user agent to be disallowed, got %v", err)
+	}
+
+	if robotsRequests != 1 || robotsUserAgent != "GooseBot/1.0" {
+		t.Errorf("expected robots.txt to be fetched once as the crawler, got %d requests as %q", robotsRequests, robotsUserAgent)
+	}
+	delay, err := config.Robots.CrawlDelay(context.Background(), ts.URL+"/", "otherbot")
+	if err != nil || delay != 200*time.Millisecond {
+		t.Errorf("expected a 200ms crawl delay, got %s (%v)", delay, err)
+	}
+}
+
+func Test_RobotsCheckerMissingFile(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("<html><head><title>allowed</title></head></html>"))
+	}))
+	defer ts.Close()
+
+	config := GetDefaultConfiguration()
+	config.Robots = NewRobotsChecker(nil)
+	if _, err := NewWithConfig(config).ExtractFromURL(ts.URL + "/page"); err != nil {
+		t.Errorf("expected a missing robots.txt to allow everything, got %v", err)
+	}
+}
+
+func Test_RobotsCheckerProductToken(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("User-agent: Safari\nUser-agent: Go\nDisallow: /\n\nUser-agent: GooseBot\nDisallow: /private\n\nUser-agent: *\nDisallow: /admin\n"))
+	}))
+	defer ts.Close()
+
+	checker := NewRobotsChecker(nil)
+	tests := []struct {
+		userAgent string
+		path      string
+		allowed   bool
+	}{
+		// the tokens found elsewhere in the user agent do not select a group
+		{GetDefaultConfiguration().BrowserUserAgent, "/page", true},
+		{GetDefaultConfiguration().BrowserUserAgent, "/admin", false},
+		{"Go-http-client/1.1", "/page", true},
+		{"Go/1.21", "/page", false},
+		{"goosebot/2.0 (+https://example.com/bot)", "/private", false},
+		{"goosebot/2.0 (+https://example.com/bot)", "/admin", true},
+	}
+	for _, test := range tests {
+		err := checker.Check(context.Background(), ts.URL+test.path, test.userAgent)
+		if allowed := err == nil; allowed != test.allowed {
+			t.Errorf("%s %s: expected allowed=%v, got %v", test.userAgent, test.path, test.allowed, err)
+		}
+	}
+}
+
+func Test_RobotsCheckerLargeFile(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.Write([]byte(strings.Repeat("# padding\n", 60<<10)))
+			return
+		}
+		w.Write([]byte("<html><head><title>allowed</title></head></html>"))
+	}))
+	defer ts.Close()
+
+	config := GetDefaultConfiguration()
+	config.Robots = NewRobotsChecker(nil)
+	_, err := NewWithConfig(config).ExtractFromURL(ts.URL + "/page")
+	var robotsErr *RobotsDisallowedError
+	if !errors.As(err, &robotsErr) {
+		t.Errorf("expected a robots.txt over 500 KiB to be considered unreachable, got %v", err)
+	}
+}
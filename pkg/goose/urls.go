@@ -0,0 +1,20 @@
+package goose
+
+import (
+	"github.com/advancedlogic/GoOse/internal/utils"
+)
+
+// DefaultTrackingParams are the query parameters removed from the canonical links by default
+var DefaultTrackingParams = utils.DefaultTrackingParams
+
+// NormalizeURL returns the normalised form of a URL, resolved against base when it is relative,
+// as used for Article.CanonicalLink: lowercased host, no default port nor fragment, no tracking
+// parameters, sorted query, and unwrapped AMP and mobile variants
+func NormalizeURL(rawURL string, base string, trackingParams []string) (string, error) {
+	return utils.NormalizeURL(rawURL, base, trackingParams)
+}
+
+// HashURL returns a stable hash of a normalised URL, as used for Article.LinkHash
+func HashURL(normalizedURL string) string {
+	return utils.HashURL(normalizedURL)
+}
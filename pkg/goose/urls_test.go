@@ -0,0 +1,65 @@
+package goose
+
+import "testing"
+
+func Test_NormalizeURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		base     string
+		expected string
+	}{
+		{"HTTPS://WWW.Example.COM:443/News/Story?b=2&a=1#comments", "", "https://www.example.com/News/Story?a=1&b=2"},
+		{"http://example.com:8080", "", "http://example.com:8080/"},
+		{"/news/story?utm_source=twitter&utm_medium=social&id=42&fbclid=abc&gclid=def", "https://example.com/other", "https://example.com/news/story?id=42"},
+		{"../story", "https://example.com/news/section/page", "https://example.com/news/story"},
+		{"https://m.example.com/news/story", "", "https://example.com/news/story"},
+		{"https://en.m.wikipedia.org/wiki/Go", "", "https://en.wikipedia.org/wiki/Go"},
+		{"https://www.example.com/news/story/amp/", "", "https://www.example.com/news/story/"},
+		{"https://www.example.com/news/story.amp.html", "", "https://www.example.com/news/story.html"},
+		{"https://www.example.com/news/story?amp=1&page=2", "", "https://www.example.com/news/story?page=2"},
+		{"https://www-example-com.cdn.ampproject.org/c/s/www.example.com/news/story", "", "https://www.example.com/news/story"},
+		{"https://amp.theguardian.com/world/story", "", "https://theguardian.com/world/story"},
+		{"https://example.com/search?q=a&q=b", "", "https://example.com/search?q=a&q=b"},
+	}
+	for _, test := range tests {
+		normalized, err := NormalizeURL(test.url, test.base, DefaultTrackingParams)
+		if err != nil {
+			t.Errorf("%s: %v", test.url, err)
+			continue
+		}
+		if normalized != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.url, test.expected, normalized)
+		}
+	}
+	if _, err := NormalizeURL("mailto:jane@example.com", "", nil); err == nil {
+		t.Error("expected an error for a URL that is not a web one")
+	}
+	if normalized, _ := NormalizeURL("https://example.com/?ref=home&id=1", "", []string{"ref"}); normalized != "https://example.com/?id=1" {
+		t.Errorf("expected the configured tracking parameters to be removed, got %s", normalized)
+	}
+}
+
+func Test_CanonicalLinkHash(t *testing.T) {
+	page := `<html><head><title>Parks</title><link rel="canonical" href="/news/parks?utm_campaign=daily"></head>
+<body><p>The council approved the new budget for the city parks after a long debate.</p></body></html>`
+	article, err := New().ExtractFromRawHTML(page, "https://M.Example.com/news/parks/amp?fbclid=123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.CanonicalLink != "https://example.com/news/parks" {
+		t.Errorf("unexpected canonical link %s", article.CanonicalLink)
+	}
+	if article.LinkHash != HashURL("https://example.com/news/parks") || len(article.LinkHash) != 64 {
+		t.Errorf("unexpected link hash %q", article.LinkHash)
+	}
+
+	// without a canonical link, the variants of the page get the same hash
+	page = `<html><head><title>Parks</title></head><body><p>The council approved the new budget for the city parks.</p></body></html>`
+	amp, err := New().ExtractFromRawHTML(page, "https://m.example.com/news/parks/amp?utm_source=rss")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amp.LinkHash != article.LinkHash {
+		t.Errorf("expected the same link hash for the variants of a page, got %s and %s", amp.LinkHash, article.LinkHash)
+	}
+}